@@ -0,0 +1,126 @@
+package action
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/bububa/openpose/dtw"
+)
+
+// Config represents classifier config
+type Config struct {
+	// K neighbours voting
+	K int
+	// Band Sakoe-Chiba band of dtw in frames, 0 disables it
+	Band int
+	// MaxDistance max dtw distance of the nearest clip, 0 disables it
+	MaxDistance float64
+}
+
+// DefaultConfig returns default classifier config
+func DefaultConfig() Config {
+	return Config{
+		K:           3,
+		MaxDistance: 0.5,
+	}
+}
+
+// Neighbour represents a clip close to a classified window
+type Neighbour struct {
+	// Label clip label
+	Label string
+	// Distance dtw distance
+	Distance float64
+}
+
+// Prediction represents classification result
+type Prediction struct {
+	// Label predicted action
+	Label string
+	// Confidence weighted vote share of the label in [0, 1]
+	Confidence float64
+	// Neighbours nearest clips
+	Neighbours []Neighbour
+}
+
+// Classifier classifies frame windows by kNN over dtw distance to example clips
+type Classifier struct {
+	cfg   Config
+	clips []Clip
+	mutex sync.RWMutex
+}
+
+// NewClassifier returns a new Classifier with example clips
+func NewClassifier(cfg Config, clips ...Clip) *Classifier {
+	if cfg.K <= 0 {
+		cfg.K = 1
+	}
+	return &Classifier{
+		cfg:   cfg,
+		clips: clips,
+	}
+}
+
+// Add adds example clips
+func (c *Classifier) Add(clips ...Clip) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.clips = append(c.clips, clips...)
+}
+
+// Clips returns a copy of example clips
+func (c *Classifier) Clips() []Clip {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return append([]Clip(nil), c.clips...)
+}
+
+// Distance returns dtw distance of frames a and b
+func Distance(a []Frame, b []Frame, band int) float64 {
+	return dtw.Distance(len(a), len(b), func(i int, j int) float64 {
+		return FrameDistance(a[i], b[j])
+	}, band)
+}
+
+// Classify returns prediction of frames, false without clips, if no clip is alignable within Band
+// or if the nearest clip is farther than MaxDistance
+func (c *Classifier) Classify(frames []Frame) (Prediction, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if len(frames) == 0 || len(c.clips) == 0 {
+		return Prediction{}, false
+	}
+	neighbours := make([]Neighbour, 0, len(c.clips))
+	for _, clip := range c.clips {
+		neighbours = append(neighbours, Neighbour{
+			Label:    clip.Label,
+			Distance: Distance(frames, clip.Frames, c.cfg.Band),
+		})
+	}
+	sort.SliceStable(neighbours, func(i, j int) bool { return neighbours[i].Distance < neighbours[j].Distance })
+	if len(neighbours) > c.cfg.K {
+		neighbours = neighbours[:c.cfg.K]
+	}
+	// +Inf distance means lengths differ too much to align within band
+	if math.IsInf(neighbours[0].Distance, 1) || c.cfg.MaxDistance > 0 && neighbours[0].Distance > c.cfg.MaxDistance {
+		return Prediction{Neighbours: neighbours}, false
+	}
+	var (
+		votes = make(map[string]float64, len(neighbours))
+		total float64
+	)
+	for _, neighbour := range neighbours {
+		weight := 1 / (neighbour.Distance + 1e-6)
+		votes[neighbour.Label] += weight
+		total += weight
+	}
+	ret := Prediction{Neighbours: neighbours}
+	for _, neighbour := range neighbours {
+		if vote := votes[neighbour.Label]; vote > ret.Confidence*total {
+			ret.Label = neighbour.Label
+			ret.Confidence = vote / total
+		}
+	}
+	return ret, true
+}
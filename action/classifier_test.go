@@ -0,0 +1,113 @@
+package action
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/internal/posetest"
+)
+
+// pose returns a standing human in a 640x480 image of action at phase in [0, 1]
+func pose(action string, phase float64) openpose.Human {
+	swing := math.Sin(2 * math.Pi * phase)
+	points := map[openpose.CocoPart]openpose.Point{
+		openpose.CocoPartNose:      openpose.Pt(320, 60),
+		openpose.CocoPartNeck:      openpose.Pt(320, 100),
+		openpose.CocoPartRShoulder: openpose.Pt(290, 100),
+		openpose.CocoPartLShoulder: openpose.Pt(350, 100),
+		openpose.CocoPartRElbow:    openpose.Pt(280, 160),
+		openpose.CocoPartLElbow:    openpose.Pt(360, 160),
+		openpose.CocoPartRWrist:    openpose.Pt(275, 220),
+		openpose.CocoPartLWrist:    openpose.Pt(365, 220),
+		openpose.CocoPartRHip:      openpose.Pt(300, 220),
+		openpose.CocoPartLHip:      openpose.Pt(340, 220),
+		openpose.CocoPartRKnee:     openpose.Pt(300, 320),
+		openpose.CocoPartLKnee:     openpose.Pt(340, 320),
+		openpose.CocoPartRAnkle:    openpose.Pt(300, 420),
+		openpose.CocoPartLAnkle:    openpose.Pt(340, 420),
+	}
+	var dy float64
+	switch action {
+	case "wave":
+		points[openpose.CocoPartRElbow] = openpose.Pt(250, 90)
+		points[openpose.CocoPartRWrist] = openpose.Pt(250+30*swing, 30)
+	case "clap":
+		for _, part := range []openpose.CocoPart{openpose.CocoPartRWrist, openpose.CocoPartLWrist} {
+			side := 1.0
+			if part == openpose.CocoPartRWrist {
+				side = -1
+			}
+			points[part] = openpose.Pt(320+side*(5+25*math.Abs(swing)), 150)
+		}
+		points[openpose.CocoPartRElbow] = openpose.Pt(280, 170)
+		points[openpose.CocoPartLElbow] = openpose.Pt(360, 170)
+	case "jump":
+		dy = -40 * math.Abs(swing)
+	}
+	for part, point := range points {
+		points[part] = openpose.Pt(point.X, point.Y+dy)
+	}
+	return posetest.Human(points, 640, 480)
+}
+
+// record returns a clip of two cycles of action in frames
+func record(action string, frames int) Clip {
+	r := NewRecorder(action, 640, 480)
+	for idx := 0; idx < frames; idx++ {
+		r.Record(pose(action, 2*float64(idx)/float64(frames)))
+	}
+	return r.Clip()
+}
+
+func TestClassifier(t *testing.T) {
+	var clips []Clip
+	for _, action := range []string{"wave", "clap", "jump"} {
+		for _, frames := range []int{30, 40, 50} {
+			clips = append(clips, record(action, frames))
+		}
+	}
+	// round trip through the clip file format
+	var buf bytes.Buffer
+	for _, clip := range clips {
+		assert.NoError(t, WriteClip(&buf, clip))
+	}
+	loaded, err := LoadClips(&buf)
+	if !assert.NoError(t, err) || !assert.Len(t, loaded, len(clips)) {
+		return
+	}
+	c := NewClassifier(DefaultConfig(), loaded...)
+	for _, action := range []string{"wave", "clap", "jump"} {
+		window := NewWindow(45)
+		for idx := 0; idx < 45; idx++ {
+			window.Push(pose(action, 2*float64(idx)/45), 640, 480)
+		}
+		prediction, ok := c.Classify(window.Frames())
+		if assert.True(t, ok, action) {
+			assert.Equal(t, action, prediction.Label)
+			assert.Equal(t, 1.0, prediction.Confidence)
+		}
+	}
+	// standing still is none of them
+	window := NewWindow(30)
+	for idx := 0; idx < 30; idx++ {
+		window.Push(pose("stand", 0), 640, 480)
+	}
+	_, ok := NewClassifier(Config{K: 3, MaxDistance: 0.1}, loaded...).Classify(window.Frames())
+	assert.False(t, ok)
+	// a few frames don't align with any clip within a narrow band
+	window = NewWindow(3)
+	for idx := 0; idx < 3; idx++ {
+		window.Push(pose("wave", 2*float64(idx)/3), 640, 480)
+	}
+	prediction, ok := NewClassifier(Config{K: 3, Band: 1}, loaded...).Classify(window.Frames())
+	assert.False(t, ok)
+	assert.Empty(t, prediction.Label)
+	// clips are copied
+	got := c.Clips()
+	got[0].Label = "changed"
+	assert.Equal(t, "wave", c.Clips()[0].Label)
+}
@@ -0,0 +1,88 @@
+package action
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/bububa/openpose"
+)
+
+// Clip represents a labelled example sequence
+type Clip struct {
+	// Label action label
+	Label string `json:"label"`
+	// Frames frame features
+	Frames []Frame `json:"frames"`
+}
+
+// Recorder records humans of a subject into a labelled clip
+type Recorder struct {
+	label  string
+	humans []openpose.Human
+	imgW   float64
+	imgH   float64
+}
+
+// NewRecorder returns a new Recorder of label for img size (w, h)
+func NewRecorder(label string, imgW float64, imgH float64) *Recorder {
+	return &Recorder{
+		label: label,
+		imgW:  imgW,
+		imgH:  imgH,
+	}
+}
+
+// Record appends a human
+func (r *Recorder) Record(h openpose.Human) {
+	r.humans = append(r.humans, h)
+}
+
+// Clip returns the recorded clip
+func (r *Recorder) Clip() Clip {
+	return Clip{
+		Label:  r.label,
+		Frames: NewSequence(r.humans, r.imgW, r.imgH),
+	}
+}
+
+// WriteClip writes clip as a json line, clips could be appended to a file
+func WriteClip(w io.Writer, clip Clip) error {
+	return json.NewEncoder(w).Encode(clip)
+}
+
+// AppendClipFile appends clip to clips file
+func AppendClipFile(filePath string, clip Clip) error {
+	fn, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer fn.Close()
+	return WriteClip(fn, clip)
+}
+
+// LoadClips decodes clips of json lines
+func LoadClips(r io.Reader) ([]Clip, error) {
+	var clips []Clip
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	for {
+		var clip Clip
+		if err := decoder.Decode(&clip); err == io.EOF {
+			return clips, nil
+		} else if err != nil {
+			return nil, err
+		}
+		clips = append(clips, clip)
+	}
+}
+
+// LoadClipsFile loads clips file
+func LoadClipsFile(filePath string) ([]Clip, error) {
+	fn, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer fn.Close()
+	return LoadClips(fn)
+}
@@ -0,0 +1,2 @@
+// Package action recognises actions from windows of pose frames with kNN over dynamic time warping
+package action
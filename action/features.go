@@ -0,0 +1,129 @@
+package action
+
+import (
+	"github.com/bububa/openpose"
+)
+
+// missingCost cost of a part visible in only one of two frames, in torso lengths
+const missingCost = 1.0
+
+// Frame represents normalized pose features of a frame
+type Frame struct {
+	// Points part points relative to body center in torso lengths
+	Points [openpose.TotalBodyParts]openpose.Point `json:"points"`
+	// Visible part visibility
+	Visible [openpose.TotalBodyParts]bool `json:"visible"`
+	// Center body center in torso lengths relative to the first frame of its sequence
+	Center openpose.Point `json:"center"`
+	// scale torso length in pixels
+	scale float64
+	// origin body center in pixels
+	origin openpose.Point
+}
+
+// NewFrame returns features of human in pixel space compared to img size (w, h), false without neck and hips
+func NewFrame(h openpose.Human, imgW float64, imgH float64) (Frame, bool) {
+	var frame Frame
+	torso, found := h.TorsoLength(imgW, imgH)
+	if !found || torso <= 0 {
+		return frame, false
+	}
+	neck, _ := h.PixelCenter([]openpose.CocoPart{openpose.CocoPartNeck}, imgW, imgH)
+	hip, _ := h.PixelCenter([]openpose.CocoPart{openpose.CocoPartRHip, openpose.CocoPartLHip}, imgW, imgH)
+	frame.origin = neck.Add(hip).Mul(0.5)
+	frame.scale = torso
+	for part := openpose.CocoPartNose; part < openpose.CocoPartBackground; part++ {
+		point, found := h.PixelCenter([]openpose.CocoPart{part}, imgW, imgH)
+		if !found {
+			continue
+		}
+		frame.Points[part] = point.Sub(frame.origin).Mul(1 / torso)
+		frame.Visible[part] = true
+	}
+	return frame, true
+}
+
+// NewSequence returns features of successive humans of a subject, humans without neck and hips are skipped.
+// Centers are relative to the first frame in its torso lengths
+func NewSequence(humans []openpose.Human, imgW float64, imgH float64) []Frame {
+	frames := make([]Frame, 0, len(humans))
+	for _, h := range humans {
+		if frame, ok := NewFrame(h, imgW, imgH); ok {
+			frames = append(frames, frame)
+		}
+	}
+	return recenter(frames)
+}
+
+// recenter sets centers of frames relative to the first frame
+func recenter(frames []Frame) []Frame {
+	if len(frames) == 0 {
+		return frames
+	}
+	first := frames[0]
+	for idx := range frames {
+		frames[idx].Center = frames[idx].origin.Sub(first.origin).Mul(1 / first.scale)
+	}
+	return frames
+}
+
+// FrameDistance returns mean distance of parts of frame a and b plus distance of their centers, in torso lengths
+func FrameDistance(a Frame, b Frame) float64 {
+	var (
+		sum   float64
+		count int
+	)
+	for part := range a.Points {
+		switch {
+		case a.Visible[part] && b.Visible[part]:
+			sum += a.Points[part].Distance(b.Points[part])
+		case a.Visible[part] || b.Visible[part]:
+			sum += missingCost
+		default:
+			continue
+		}
+		count++
+	}
+	if count == 0 {
+		return missingCost + a.Center.Distance(b.Center)
+	}
+	return sum/float64(count) + a.Center.Distance(b.Center)
+}
+
+// Window represents sliding window of frames of a subject
+type Window struct {
+	size   int
+	frames []Frame
+}
+
+// NewWindow returns a new Window of size frames
+func NewWindow(size int) *Window {
+	return &Window{
+		size:   size,
+		frames: make([]Frame, 0, size),
+	}
+}
+
+// Push appends human in pixel space compared to img size (w, h), returns the window is full
+func (w *Window) Push(h openpose.Human, imgW float64, imgH float64) bool {
+	if frame, ok := NewFrame(h, imgW, imgH); ok {
+		if len(w.frames) == w.size {
+			copy(w.frames, w.frames[1:])
+			w.frames = w.frames[:w.size-1]
+		}
+		w.frames = append(w.frames, frame)
+	}
+	return len(w.frames) == w.size
+}
+
+// Frames returns a copy of window frames with centers relative to the first one
+func (w *Window) Frames() []Frame {
+	frames := make([]Frame, len(w.frames))
+	copy(frames, w.frames)
+	return recenter(frames)
+}
+
+// Reset drops window frames
+func (w *Window) Reset() {
+	w.frames = w.frames[:0]
+}
@@ -10,6 +10,8 @@ type BodyPart struct {
 	Point Point
 	// Score confidence score
 	Score float32
+	// Synthesized reports the part is estimated from other parts or frames rather than detected
+	Synthesized bool
 }
 
 // NewBodyPart returns a new BodyPart
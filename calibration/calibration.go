@@ -0,0 +1,48 @@
+package calibration
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Calibration represents calibration file of cameras
+type Calibration struct {
+	// Cameras calibrated cameras
+	Cameras []Camera `json:"cameras"`
+}
+
+// Load loads calibration from JSON
+func Load(r io.Reader) (*Calibration, error) {
+	var ret Calibration
+	if err := json.NewDecoder(r).Decode(&ret); err != nil {
+		return nil, err
+	}
+	for idx, cam := range ret.Cameras {
+		if err := cam.Validate(); err != nil {
+			return nil, fmt.Errorf("camera %d %s: %w", idx, cam.Name, err)
+		}
+	}
+	return &ret, nil
+}
+
+// LoadFile loads calibration from JSON file
+func LoadFile(path string) (*Calibration, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	return Load(fd)
+}
+
+// Camera returns camera of name
+func (c *Calibration) Camera(name string) (Camera, bool) {
+	for _, cam := range c.Cameras {
+		if cam.Name == name {
+			return cam, true
+		}
+	}
+	return Camera{}, false
+}
@@ -0,0 +1,238 @@
+package calibration
+
+import (
+	"errors"
+	"math"
+
+	"github.com/bububa/openpose"
+)
+
+// Intrinsics represents pinhole camera intrinsics in pixels
+type Intrinsics struct {
+	// Fx focal length along x
+	Fx float64 `json:"fx"`
+	// Fy focal length along y
+	Fy float64 `json:"fy"`
+	// Cx principal point x
+	Cx float64 `json:"cx"`
+	// Cy principal point y
+	Cy float64 `json:"cy"`
+	// Skew axis skew, usually 0
+	Skew float64 `json:"skew,omitempty"`
+}
+
+// Matrix returns camera matrix K
+func (i Intrinsics) Matrix() Mat3 {
+	return Mat3{
+		{i.Fx, i.Skew, i.Cx},
+		{0, i.Fy, i.Cy},
+		{0, 0, 1},
+	}
+}
+
+// Camera represents a calibrated camera, a world point X maps to camera coordinates R*X + T
+type Camera struct {
+	// Name camera name
+	Name string `json:"name"`
+	// Width image width in pixels the calibration is for
+	Width float64 `json:"width"`
+	// Height image height in pixels the calibration is for
+	Height float64 `json:"height"`
+	// Intrinsics camera intrinsics
+	Intrinsics Intrinsics `json:"intrinsics"`
+	// Rotation world to camera rotation, identity if omitted
+	Rotation *Mat3 `json:"rotation,omitempty"`
+	// RVec world to camera rotation as Rodrigues vector, used if Rotation is omitted
+	RVec *Vec3 `json:"rvec,omitempty"`
+	// Translation world to camera translation
+	Translation Vec3 `json:"translation"`
+	// Distortion lens distortion, none if omitted
+	Distortion *Distortion `json:"distortion,omitempty"`
+	// Floor image to floor plan point correspondences of the ground plane, optional
+	Floor []Correspondence `json:"floor,omitempty"`
+}
+
+// Correspondence represents an image point and its floor plan point
+type Correspondence struct {
+	// Image point in pixels of the raw image
+	Image openpose.Point `json:"image"`
+	// Floor point in floor plan units, e.g. metres
+	Floor openpose.Point `json:"floor"`
+}
+
+// Validate returns error if camera params are invalid
+func (c Camera) Validate() error {
+	if c.Width <= 0 || c.Height <= 0 {
+		return errors.New("invalid image size")
+	}
+	if c.Intrinsics.Fx <= 0 || c.Intrinsics.Fy <= 0 {
+		return errors.New("invalid focal length")
+	}
+	if !isRotation(c.R(), 1e-3) {
+		return errors.New("invalid rotation")
+	}
+	if c.Distortion != nil {
+		return c.Distortion.Validate()
+	}
+	return nil
+}
+
+// isRotation returns m is orthonormal with determinant 1 within tolerance, sheared or scaled matrices are not
+func isRotation(m Mat3, tolerance float64) bool {
+	if math.Abs(m.Det()-1) > tolerance {
+		return false
+	}
+	mmt := m.Mul(m.Transpose())
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			want := 0.0
+			if i == j {
+				want = 1
+			}
+			if math.Abs(mmt[i][j]-want) > tolerance {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// R returns world to camera rotation
+func (c Camera) R() Mat3 {
+	switch {
+	case c.Rotation != nil:
+		return *c.Rotation
+	case c.RVec != nil:
+		return Rodrigues(*c.RVec)
+	}
+	return Identity3()
+}
+
+// Center returns camera center in world coordinates
+func (c Camera) Center() Vec3 {
+	return c.R().Transpose().MulVec(c.Translation).Mul(-1)
+}
+
+// ToCamera returns camera coordinates of world point p
+func (c Camera) ToCamera(p Vec3) Vec3 {
+	return c.R().MulVec(p).Add(c.Translation)
+}
+
+// Projection returns 3x4 projection matrix K[R|T]
+func (c Camera) Projection() [3][4]float64 {
+	k, r := c.Intrinsics.Matrix(), c.R()
+	kr, kt := k.Mul(r), k.MulVec(c.Translation)
+	var ret [3][4]float64
+	for i := 0; i < 3; i++ {
+		copy(ret[i][:3], kr[i][:])
+		ret[i][3] = kt[i]
+	}
+	return ret
+}
+
+// Project returns ideal pinhole pixel point of world point p, false if p is behind the camera.
+// Use Distort for the pixel point in the raw image
+func (c Camera) Project(p Vec3) (openpose.Point, bool) {
+	pc := c.ToCamera(p)
+	if pc[2] <= 1e-12 {
+		return openpose.ZP, false
+	}
+	return c.pixel(openpose.Pt(pc[0]/pc[2], pc[1]/pc[2])), true
+}
+
+// Pixel returns pixel point of normalized body part point
+func (c Camera) Pixel(p openpose.Point) openpose.Point {
+	return p.Scale(c.Width, c.Height)
+}
+
+// Scaled returns camera calibrated for image size (w, h), e.g. the same camera streaming at a lower resolution
+func (c Camera) Scaled(w float64, h float64) Camera {
+	if c.Width <= 0 || c.Height <= 0 || (w == c.Width && h == c.Height) {
+		return c
+	}
+	sx, sy := w/c.Width, h/c.Height
+	c.Intrinsics.Fx *= sx
+	c.Intrinsics.Skew *= sx
+	c.Intrinsics.Cx *= sx
+	c.Intrinsics.Fy *= sy
+	c.Intrinsics.Cy *= sy
+	floor := make([]Correspondence, 0, len(c.Floor))
+	for _, pair := range c.Floor {
+		pair.Image = pair.Image.Scale(sx, sy)
+		floor = append(floor, pair)
+	}
+	c.Floor = floor
+	c.Width, c.Height = w, h
+	return c
+}
+
+// normalized returns normalized image coordinates of pixel point p
+func (c Camera) normalized(p openpose.Point) openpose.Point {
+	i := c.Intrinsics
+	y := (p.Y - i.Cy) / i.Fy
+	return openpose.Pt((p.X-i.Cx-i.Skew*y)/i.Fx, y)
+}
+
+// pixel returns pixel point of normalized image coordinates p
+func (c Camera) pixel(p openpose.Point) openpose.Point {
+	i := c.Intrinsics
+	return openpose.Pt(i.Fx*p.X+i.Skew*p.Y+i.Cx, i.Fy*p.Y+i.Cy)
+}
+
+// Undistort returns ideal pinhole pixel point of raw image pixel point p
+func (c Camera) Undistort(p openpose.Point) openpose.Point {
+	if c.Distortion == nil {
+		return p
+	}
+	return c.pixel(c.Distortion.Undistort(c.normalized(p)))
+}
+
+// Distort returns raw image pixel point of ideal pinhole pixel point p
+func (c Camera) Distort(p openpose.Point) openpose.Point {
+	if c.Distortion == nil {
+		return p
+	}
+	return c.pixel(c.Distortion.Distort(c.normalized(p)))
+}
+
+// UndistortHuman returns human with undistorted body part points, points stay normalized to the image size
+func (c Camera) UndistortHuman(h openpose.Human) openpose.Human {
+	if c.Distortion == nil {
+		return h
+	}
+	ret := openpose.Human{
+		Parts: make(map[openpose.CocoPart]openpose.BodyPart, len(h.Parts)),
+		Score: h.Score,
+	}
+	for part, bodyPart := range h.Parts {
+		p := c.Undistort(c.Pixel(bodyPart.Point))
+		bodyPart.Point = openpose.Pt(p.X/c.Width, p.Y/c.Height)
+		ret.Parts[part] = bodyPart
+	}
+	return ret
+}
+
+// Fundamental returns fundamental matrix F of cameras a and b, pixel points xa of a and xb of b
+// of the same world point satisfy xb^T F xa = 0
+func Fundamental(a Camera, b Camera) (Mat3, error) {
+	kaInv, okA := a.Intrinsics.Matrix().Inverse()
+	kbInv, okB := b.Intrinsics.Matrix().Inverse()
+	if !okA || !okB {
+		return Mat3{}, errors.New("singular camera matrix")
+	}
+	// relative pose from a to b
+	ra, rb := a.R(), b.R()
+	r := rb.Mul(ra.Transpose())
+	t := b.Translation.Sub(r.MulVec(a.Translation))
+	return kbInv.Transpose().Mul(Skew(t)).Mul(r).Mul(kaInv), nil
+}
+
+// EpipolarDistance returns pixel distance of xb to the epipolar line F*xa in the other view
+func EpipolarDistance(f Mat3, xa openpose.Point, xb openpose.Point) float64 {
+	line := f.MulVec(Vec3{xa.X, xa.Y, 1})
+	norm := math.Hypot(line[0], line[1])
+	if norm <= 1e-15 {
+		return math.Inf(1)
+	}
+	return math.Abs(line[0]*xb.X+line[1]*xb.Y+line[2]) / norm
+}
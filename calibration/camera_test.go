@@ -0,0 +1,69 @@
+package calibration
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bububa/openpose"
+)
+
+const calibrationJSON = `{"cameras": [
+	{"name": "front", "width": 640, "height": 480, "intrinsics": {"fx": 500, "fy": 500, "cx": 320, "cy": 240}, "translation": [0, 0, 5]},
+	{"name": "side", "width": 640, "height": 480, "intrinsics": {"fx": 500, "fy": 500, "cx": 320, "cy": 240}, "rvec": [0, -1.5707963267948966, 0], "translation": [0, 0, 5]}
+]}`
+
+func TestLoad(t *testing.T) {
+	calib, err := Load(strings.NewReader(calibrationJSON))
+	if !assert.NoError(t, err) || !assert.Len(t, calib.Cameras, 2) {
+		return
+	}
+	front, _ := calib.Camera("front")
+	side, found := calib.Camera("side")
+	assert.True(t, found)
+	center := side.Center()
+	assert.InDelta(t, -5, center[0], 1e-9)
+	assert.InDelta(t, 0, center[2], 1e-9)
+	p := Vec3{0.3, -0.2, 0.4}
+	xa, ok := front.Project(p)
+	assert.True(t, ok)
+	assert.InDelta(t, 320+500*0.3/5.4, xa.X, 1e-9)
+	xb, ok := side.Project(p)
+	assert.True(t, ok)
+	f, err := Fundamental(front, side)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0, EpipolarDistance(f, xa, xb), 1e-6)
+	assert.Greater(t, EpipolarDistance(f, xa, xb.Add(openpose.Pt(0, 30))), 10.0)
+	_, err = Load(strings.NewReader(`{"cameras": [{"name": "bad", "width": 640, "height": 480}]}`))
+	assert.Error(t, err)
+}
+
+func TestCamera_Validate(t *testing.T) {
+	cam := Camera{Width: 640, Height: 480, Intrinsics: Intrinsics{Fx: 500, Fy: 500, Cx: 320, Cy: 240}}
+	rotation := Rodrigues(Vec3{0.1, -0.4, 0.2})
+	cam.Rotation = &rotation
+	assert.NoError(t, cam.Validate())
+	// determinant 1 but not a rotation
+	for _, m := range []Mat3{
+		{{1, 0.5, 0}, {0, 1, 0}, {0, 0, 1}},
+		{{2, 0, 0}, {0, 0.5, 0}, {0, 0, 1}},
+		{{-1, 0, 0}, {0, -1, 0}, {0, 0, -1}},
+	} {
+		m := m
+		cam.Rotation = &m
+		assert.Error(t, cam.Validate(), "%v", m)
+	}
+}
+
+func TestRodrigues(t *testing.T) {
+	r := Rodrigues(Vec3{0, 0, math.Pi / 2})
+	v := r.MulVec(Vec3{1, 0, 0})
+	assert.InDelta(t, 0, v[0], 1e-9)
+	assert.InDelta(t, 1, v[1], 1e-9)
+	inv, ok := r.Inverse()
+	assert.True(t, ok)
+	assert.InDelta(t, 0, inv.Mul(r)[0][1], 1e-9)
+	assert.InDelta(t, 1, inv.Mul(r)[2][2], 1e-9)
+}
@@ -0,0 +1,164 @@
+package calibration
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/bububa/openpose"
+)
+
+// DistortionModel represents lens distortion model
+type DistortionModel string
+
+const (
+	// BrownConrady radial and tangential distortion with coefficients k1, k2, p1, p2, k3 in OpenCV order
+	BrownConrady DistortionModel = "brown"
+	// Fisheye equidistant fisheye distortion with coefficients k1, k2, k3, k4 as OpenCV fisheye
+	Fisheye DistortionModel = "fisheye"
+)
+
+// undistortIterations iterations to invert distortion
+const undistortIterations = 20
+
+// maxFisheyeTheta max incidence angle of undistorted fisheye points, rays at 90 degrees never reach the image plane
+const maxFisheyeTheta = 89 * math.Pi / 180
+
+// Distortion represents lens distortion of normalized image coordinates
+type Distortion struct {
+	// Model distortion model
+	Model DistortionModel `json:"model"`
+	// Coeffs distortion coefficients of the model, missing trailing coefficients are 0
+	Coeffs []float64 `json:"coeffs"`
+}
+
+// Validate returns error if the model is unknown or has too many coefficients
+func (d Distortion) Validate() error {
+	switch d.Model {
+	case BrownConrady:
+		if len(d.Coeffs) > 5 {
+			return fmt.Errorf("%s distortion takes at most 5 coefficients", d.Model)
+		}
+	case Fisheye:
+		if len(d.Coeffs) > 4 {
+			return fmt.Errorf("%s distortion takes at most 4 coefficients", d.Model)
+		}
+	default:
+		return fmt.Errorf("unknown distortion model: %s", d.Model)
+	}
+	return nil
+}
+
+// coeff returns coefficient at idx, 0 if missing
+func (d Distortion) coeff(idx int) float64 {
+	if idx < len(d.Coeffs) {
+		return d.Coeffs[idx]
+	}
+	return 0
+}
+
+// Distort returns distorted normalized point of undistorted normalized point p
+func (d Distortion) Distort(p openpose.Point) openpose.Point {
+	if d.Model == Fisheye {
+		r := p.Norm()
+		if r <= 1e-15 {
+			return p
+		}
+		theta := math.Atan(r)
+		return p.Mul(d.fisheyeTheta(theta) / r)
+	}
+	k1, k2, p1, p2, k3 := d.coeff(0), d.coeff(1), d.coeff(2), d.coeff(3), d.coeff(4)
+	x, y := p.X, p.Y
+	r2 := x*x + y*y
+	radial := 1 + r2*(k1+r2*(k2+r2*k3))
+	return openpose.Pt(
+		x*radial+2*p1*x*y+p2*(r2+2*x*x),
+		y*radial+p1*(r2+2*y*y)+2*p2*x*y,
+	)
+}
+
+// Undistort returns undistorted normalized point of distorted normalized point p, inverted iteratively.
+// Fisheye points beyond the field of view of the model are clamped to its edge, see fisheyeMaxTheta
+func (d Distortion) Undistort(p openpose.Point) openpose.Point {
+	if d.Model == Fisheye {
+		thetaD := p.Norm()
+		if thetaD <= 1e-15 {
+			return p
+		}
+		lo, hi := 0.0, d.fisheyeMaxTheta()
+		if d.fisheyeTheta(hi) <= thetaD {
+			return p.Mul(math.Tan(hi) / thetaD)
+		}
+		// Newton's method on theta_d(theta) = thetaD, bisecting the bracket [lo, hi] when a step leaves it
+		theta := math.Min(thetaD, hi)
+		for i := 0; i < undistortIterations; i++ {
+			diff := d.fisheyeTheta(theta) - thetaD
+			if math.Abs(diff) <= 1e-15 {
+				break
+			}
+			if diff > 0 {
+				hi = theta
+			} else {
+				lo = theta
+			}
+			derivative := d.fisheyeDerivative(theta)
+			next := theta - diff/derivative
+			if derivative <= 1e-15 || next <= lo || next >= hi {
+				next = (lo + hi) / 2
+			}
+			step := next - theta
+			theta = next
+			if math.Abs(step) <= 1e-12 {
+				break
+			}
+		}
+		return p.Mul(math.Tan(theta) / thetaD)
+	}
+	k1, k2, p1, p2, k3 := d.coeff(0), d.coeff(1), d.coeff(2), d.coeff(3), d.coeff(4)
+	x, y := p.X, p.Y
+	for i := 0; i < undistortIterations; i++ {
+		r2 := x*x + y*y
+		radial := 1 + r2*(k1+r2*(k2+r2*k3))
+		if math.Abs(radial) <= 1e-15 {
+			break
+		}
+		dx := 2*p1*x*y + p2*(r2+2*x*x)
+		dy := p1*(r2+2*y*y) + 2*p2*x*y
+		x = (p.X - dx) / radial
+		y = (p.Y - dy) / radial
+	}
+	return openpose.Pt(x, y)
+}
+
+// fisheyeTheta returns distorted angle of incidence angle theta
+func (d Distortion) fisheyeTheta(theta float64) float64 {
+	t2 := theta * theta
+	return theta * (1 + t2*(d.coeff(0)+t2*(d.coeff(1)+t2*(d.coeff(2)+t2*d.coeff(3)))))
+}
+
+// fisheyeDerivative returns derivative of fisheyeTheta at theta
+func (d Distortion) fisheyeDerivative(theta float64) float64 {
+	t2 := theta * theta
+	return 1 + t2*(3*d.coeff(0)+t2*(5*d.coeff(1)+t2*(7*d.coeff(2)+t2*9*d.coeff(3))))
+}
+
+// fisheyeMaxTheta returns the edge of the field of view of the model, the incidence angle where distorted angle
+// stops increasing, at most maxFisheyeTheta. Distorted angles beyond it have no or ambiguous incidence angles
+func (d Distortion) fisheyeMaxTheta() float64 {
+	const step = math.Pi / 180
+	for theta := step; theta < maxFisheyeTheta; theta += step {
+		if d.fisheyeDerivative(theta) > 0 {
+			continue
+		}
+		// bisect the root of derivative in the last step
+		lo, hi := theta-step, theta
+		for i := 0; i < undistortIterations; i++ {
+			if mid := (lo + hi) / 2; d.fisheyeDerivative(mid) > 0 {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		return lo
+	}
+	return maxFisheyeTheta
+}
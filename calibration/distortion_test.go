@@ -0,0 +1,94 @@
+package calibration
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bububa/openpose"
+)
+
+func TestDistortion_RoundTrip(t *testing.T) {
+	for _, d := range []Distortion{
+		{Model: BrownConrady, Coeffs: []float64{-0.28, 0.07, 0.001, -0.0005, 0}},
+		{Model: Fisheye, Coeffs: []float64{0.05, -0.01, 0.002}},
+	} {
+		assert.NoError(t, d.Validate())
+		for _, p := range []openpose.Point{openpose.Pt(0, 0), openpose.Pt(0.3, -0.2), openpose.Pt(-0.5, 0.4)} {
+			back := d.Undistort(d.Distort(p))
+			assert.InDelta(t, p.X, back.X, 1e-6, "%s", d.Model)
+			assert.InDelta(t, p.Y, back.Y, 1e-6, "%s", d.Model)
+		}
+	}
+	// a strong negative k1 folds theta_d over at theta 1/sqrt(0.9), farther points are clamped to the edge of the view
+	d := Distortion{Model: Fisheye, Coeffs: []float64{-0.3}}
+	for _, p := range []openpose.Point{openpose.Pt(0.6, 0.8), openpose.Pt(-3, 4)} {
+		back := d.Undistort(p)
+		assert.InDelta(t, math.Tan(1/math.Sqrt(0.9)), back.Norm(), 1e-5)
+		assert.InDelta(t, 0, back.X*p.Y-back.Y*p.X, 1e-9)
+		assert.Greater(t, back.X*p.X, 0.0)
+	}
+	// points within the view still round trip
+	for _, p := range []openpose.Point{openpose.Pt(0.3, 0.4), openpose.Pt(-0.9, 0.9)} {
+		back := d.Undistort(d.Distort(p))
+		assert.InDelta(t, p.X, back.X, 1e-6)
+		assert.InDelta(t, p.Y, back.Y, 1e-6)
+	}
+	// without folding the view ends at maxFisheyeTheta
+	d = Distortion{Model: Fisheye, Coeffs: []float64{0.05}}
+	assert.InDelta(t, math.Tan(maxFisheyeTheta), d.Undistort(openpose.Pt(0, 10)).Y, 1e-9)
+	assert.Error(t, Distortion{Model: "lens"}.Validate())
+	assert.Error(t, Distortion{Model: Fisheye, Coeffs: make([]float64, 5)}.Validate())
+}
+
+func TestCamera_Undistort(t *testing.T) {
+	cam := Camera{
+		Width:      640,
+		Height:     480,
+		Intrinsics: Intrinsics{Fx: 400, Fy: 400, Cx: 320, Cy: 240},
+		Distortion: &Distortion{Model: BrownConrady, Coeffs: []float64{-0.3, 0.08}},
+	}
+	assert.NoError(t, cam.Validate())
+	// barrel distortion pulls corners towards the center
+	ideal := openpose.Pt(600, 450)
+	raw := cam.Distort(ideal)
+	assert.Less(t, raw.X, ideal.X)
+	back := cam.Undistort(raw)
+	assert.InDelta(t, ideal.X, back.X, 1e-4)
+	assert.InDelta(t, ideal.Y, back.Y, 1e-4)
+	// the same camera at half resolution
+	half := cam.Scaled(320, 240)
+	rawHalf := half.Distort(ideal.Mul(0.5))
+	assert.InDelta(t, raw.X/2, rawHalf.X, 1e-6)
+	h := openpose.NewHuman()
+	h.Parts[openpose.CocoPartNose] = openpose.NewBodyPart(openpose.CocoPartNose, openpose.Pt(raw.X/640, raw.Y/480), 0.9)
+	undistorted := cam.UndistortHuman(*h)
+	assert.InDelta(t, ideal.X/640, undistorted.Parts[openpose.CocoPartNose].Point.X, 1e-6)
+	assert.InDelta(t, ideal.Y/480, undistorted.Parts[openpose.CocoPartNose].Point.Y, 1e-6)
+}
+
+func TestUndistorter_Undistort(t *testing.T) {
+	cam := Camera{
+		Width:      160,
+		Height:     120,
+		Intrinsics: Intrinsics{Fx: 100, Fy: 100, Cx: 80, Cy: 60},
+		Distortion: &Distortion{Model: Fisheye, Coeffs: []float64{0.1, 0.02}},
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 160, 120))
+	ideal := openpose.Pt(140.5, 100.5)
+	raw := cam.Distort(ideal)
+	for y := int(raw.Y) - 1; y <= int(raw.Y)+1; y++ {
+		for x := int(raw.X) - 1; x <= int(raw.X)+1; x++ {
+			img.SetRGBA(x, y, color.RGBA{255, 255, 255, 255})
+		}
+	}
+	out := NewUndistorter(cam).Undistort(img)
+	assert.Equal(t, img.Bounds(), out.Bounds())
+	r, _, _, _ := out.At(int(ideal.X), int(ideal.Y)).RGBA()
+	assert.Equal(t, uint32(0xffff), r)
+	r, _, _, _ = out.At(int(raw.X), int(raw.Y)).RGBA()
+	assert.Equal(t, uint32(0), r)
+}
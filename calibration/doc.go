@@ -0,0 +1,2 @@
+// Package calibration represents calibrated pinhole cameras and their calibration file
+package calibration
@@ -0,0 +1,140 @@
+package calibration
+
+import (
+	"math"
+)
+
+// Vec3 represents a 3D vector
+type Vec3 [3]float64
+
+// Add returns v + o
+func (v Vec3) Add(o Vec3) Vec3 {
+	return Vec3{v[0] + o[0], v[1] + o[1], v[2] + o[2]}
+}
+
+// Sub returns v - o
+func (v Vec3) Sub(o Vec3) Vec3 {
+	return Vec3{v[0] - o[0], v[1] - o[1], v[2] - o[2]}
+}
+
+// Mul returns v scaled by s
+func (v Vec3) Mul(s float64) Vec3 {
+	return Vec3{v[0] * s, v[1] * s, v[2] * s}
+}
+
+// Dot returns dot product of v and o
+func (v Vec3) Dot(o Vec3) float64 {
+	return v[0]*o[0] + v[1]*o[1] + v[2]*o[2]
+}
+
+// Cross returns cross product of v and o
+func (v Vec3) Cross(o Vec3) Vec3 {
+	return Vec3{
+		v[1]*o[2] - v[2]*o[1],
+		v[2]*o[0] - v[0]*o[2],
+		v[0]*o[1] - v[1]*o[0],
+	}
+}
+
+// Norm returns length of v
+func (v Vec3) Norm() float64 {
+	return math.Sqrt(v.Dot(v))
+}
+
+// Distance returns distance between v and o
+func (v Vec3) Distance(o Vec3) float64 {
+	return v.Sub(o).Norm()
+}
+
+// Mat3 represents a 3x3 matrix in row major order
+type Mat3 [3][3]float64
+
+// Identity3 returns identity matrix
+func Identity3() Mat3 {
+	return Mat3{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+}
+
+// Mul returns m * o
+func (m Mat3) Mul(o Mat3) Mat3 {
+	var ret Mat3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				ret[i][j] += m[i][k] * o[k][j]
+			}
+		}
+	}
+	return ret
+}
+
+// MulVec returns m * v
+func (m Mat3) MulVec(v Vec3) Vec3 {
+	return Vec3{
+		m[0][0]*v[0] + m[0][1]*v[1] + m[0][2]*v[2],
+		m[1][0]*v[0] + m[1][1]*v[1] + m[1][2]*v[2],
+		m[2][0]*v[0] + m[2][1]*v[1] + m[2][2]*v[2],
+	}
+}
+
+// Transpose returns transpose of m
+func (m Mat3) Transpose() Mat3 {
+	var ret Mat3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			ret[i][j] = m[j][i]
+		}
+	}
+	return ret
+}
+
+// Det returns determinant of m
+func (m Mat3) Det() float64 {
+	return m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+}
+
+// Inverse returns inverse of m, false if m is singular
+func (m Mat3) Inverse() (Mat3, bool) {
+	det := m.Det()
+	if math.Abs(det) <= 1e-15 {
+		return Mat3{}, false
+	}
+	var ret Mat3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			// cofactor of (j, i)
+			r0, r1 := (j+1)%3, (j+2)%3
+			c0, c1 := (i+1)%3, (i+2)%3
+			ret[i][j] = (m[r0][c0]*m[r1][c1] - m[r0][c1]*m[r1][c0]) / det
+		}
+	}
+	return ret, true
+}
+
+// Skew returns cross product matrix of v, Skew(v) * o = v x o
+func Skew(v Vec3) Mat3 {
+	return Mat3{
+		{0, -v[2], v[1]},
+		{v[2], 0, -v[0]},
+		{-v[1], v[0], 0},
+	}
+}
+
+// Rodrigues returns rotation matrix of axis angle vector v in radians, as OpenCV rvec
+func Rodrigues(v Vec3) Mat3 {
+	theta := v.Norm()
+	if theta <= 1e-15 {
+		return Identity3()
+	}
+	k := Skew(v.Mul(1 / theta))
+	k2 := k.Mul(k)
+	ret := Identity3()
+	s, c := math.Sin(theta), 1-math.Cos(theta)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			ret[i][j] += s*k[i][j] + c*k2[i][j]
+		}
+	}
+	return ret
+}
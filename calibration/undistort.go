@@ -0,0 +1,104 @@
+package calibration
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"sync"
+
+	"github.com/bububa/openpose"
+)
+
+// Undistorter undistorts frames of a camera by a remap table cached per frame size
+type Undistorter struct {
+	cam   Camera
+	size  image.Point
+	remap [][2]float32
+	mutex sync.Mutex
+}
+
+// NewUndistorter returns a new Undistorter of camera
+func NewUndistorter(cam Camera) *Undistorter {
+	return &Undistorter{cam: cam}
+}
+
+// table returns remap table of frame size, raw image pixel of each ideal pixel center in row major order.
+// Pixels are float32 pairs, sub-pixel precise for any frame size and half the memory of points
+func (u *Undistorter) table(size image.Point) [][2]float32 {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	if u.remap != nil && u.size == size {
+		return u.remap
+	}
+	cam := u.cam.Scaled(float64(size.X), float64(size.Y))
+	remap := make([][2]float32, 0, size.X*size.Y)
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			p := cam.Distort(openpose.Pt(float64(x)+0.5, float64(y)+0.5))
+			remap = append(remap, [2]float32{float32(p.X), float32(p.Y)})
+		}
+	}
+	u.size, u.remap = size, remap
+	return remap
+}
+
+// Undistort returns undistorted img of the same size by bilinear sampling, pixels mapped outside img are transparent
+func (u *Undistorter) Undistort(img image.Image) image.Image {
+	bounds := img.Bounds()
+	if u.cam.Distortion == nil {
+		return img
+	}
+	src, ok := img.(*image.RGBA)
+	if !ok {
+		src = image.NewRGBA(bounds)
+		draw.Draw(src, bounds, img, bounds.Min, draw.Src)
+	}
+	remap := u.table(bounds.Size())
+	out := image.NewRGBA(bounds)
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			p := remap[y*bounds.Dx()+x]
+			out.SetRGBA(bounds.Min.X+x, bounds.Min.Y+y, bilinear(src, float64(p[0])-0.5, float64(p[1])-0.5))
+		}
+	}
+	return out
+}
+
+// bilinear returns color of img at (x, y) relative to its bounds min, in pixel center coordinates
+func bilinear(img *image.RGBA, x float64, y float64) color.RGBA {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if x < -0.5 || y < -0.5 || x > float64(w)-0.5 || y > float64(h)-0.5 {
+		return color.RGBA{}
+	}
+	x0, y0 := int(math.Floor(x)), int(math.Floor(y))
+	fx, fy := x-float64(x0), y-float64(y0)
+	at := func(px int, py int) color.RGBA {
+		px = clamp(px, 0, w-1)
+		py = clamp(py, 0, h-1)
+		return img.RGBAAt(bounds.Min.X+px, bounds.Min.Y+py)
+	}
+	c00, c10, c01, c11 := at(x0, y0), at(x0+1, y0), at(x0, y0+1), at(x0+1, y0+1)
+	mix := func(a, b, c, d uint8) uint8 {
+		top := float64(a)*(1-fx) + float64(b)*fx
+		bottom := float64(c)*(1-fx) + float64(d)*fx
+		return uint8(math.Round(top*(1-fy) + bottom*fy))
+	}
+	return color.RGBA{
+		R: mix(c00.R, c10.R, c01.R, c11.R),
+		G: mix(c00.G, c10.G, c01.G, c11.G),
+		B: mix(c00.B, c10.B, c01.B, c11.B),
+		A: mix(c00.A, c10.A, c01.A, c11.A),
+	}
+}
+
+func clamp(v int, min int, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
@@ -16,15 +16,34 @@ import (
 
 	"github.com/bububa/camera"
 	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/calibration"
 	"github.com/bububa/openpose/cmd/camera/server"
+	"github.com/bububa/openpose/fall"
+	"github.com/bububa/openpose/ground"
+	"github.com/bububa/openpose/interpolate"
+	"github.com/bububa/openpose/proximity"
+	"github.com/bububa/openpose/rules"
+	"github.com/bububa/openpose/smoothing"
+	"github.com/bububa/openpose/swap"
 )
 
 var (
-	opts      camera.Options
-	estimator *openpose.PoseEstimator
-	bind      string
-	modelPath string
-	modelType string
+	opts       camera.Options
+	estimator  *openpose.PoseEstimator
+	bind       string
+	modelPath  string
+	modelType  string
+	rulesPath  string
+	smooth     string
+	fill       bool
+	fixSwaps   bool
+	detectFall bool
+	floorPath  string
+	distance   float64
+	calibPath  string
+	undistort  bool
+	rotate     float64
+	flip       string
 )
 
 func init() {
@@ -35,6 +54,17 @@ func init() {
 	flag.StringVar(&bind, "bind", ":8080", "set server bind")
 	flag.StringVar(&modelPath, "model", "", "set openpose model path")
 	flag.StringVar(&modelType, "model-type", "mobilenet", "set openpose model type")
+	flag.Float64Var(&rotate, "rotate", 0, "rotate frames counter-clockwise in degrees")
+	flag.StringVar(&flip, "flip", "", "flip frames, h for mirror, v for upside down or hv for both")
+	flag.StringVar(&rulesPath, "rules", "", "set pose condition rules file path")
+	flag.BoolVar(&detectFall, "fall", false, "detect falls of tracked humans")
+	flag.BoolVar(&fixSwaps, "fix-swaps", false, "correct left/right swapped keypoints of tracked humans")
+	flag.BoolVar(&fill, "fill", false, "fill missing keypoints of tracked humans")
+	flag.StringVar(&smooth, "smooth", "", "set keypoint smoothing filter, oneeuro or kalman")
+	flag.StringVar(&floorPath, "floor", "", "set image to floor point correspondences file path, enables proximity alerts")
+	flag.StringVar(&calibPath, "calibration", "", "set camera calibration file path, the first camera is used")
+	flag.BoolVar(&undistort, "undistort", false, "undistort frames by lens distortion of the calibrated camera")
+	flag.Float64Var(&distance, "distance", proximity.DefaultConfig().Radius, "set proximity alert radius in floor units")
 }
 
 func setup() error {
@@ -73,6 +103,87 @@ func main() {
 	srv := server.New(bind, estimator, cam)
 	srv.SetFrameSize(opts.Width, opts.Height)
 	srv.SetDelay(opts.Delay)
+	var transform openpose.Transform
+	if rotate != 0 {
+		transform = transform.Rotate(rotate)
+	}
+	if strings.Contains(flip, "h") {
+		transform = transform.FlipH()
+	}
+	if strings.Contains(flip, "v") {
+		transform = transform.FlipV()
+	}
+	srv.SetTransform(transform)
+	if rulesPath != "" {
+		rs, err := rules.LoadRulesFile(rulesPath)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		srv.SetRules(rs)
+	}
+	if fixSwaps {
+		srv.SetSwapCorrector(swap.NewCorrector(swap.DefaultConfig()))
+	}
+	if detectFall {
+		srv.SetFallDetector(fall.NewDetector(fall.DefaultConfig()))
+	}
+	if fill {
+		srv.SetFiller(interpolate.NewFiller(interpolate.DefaultConfig()))
+	}
+	switch smooth {
+	case "oneeuro":
+		srv.SetSmoother(smoothing.NewOneEuroSmoother(smoothing.DefaultOneEuroConfig(), time.Second))
+	case "kalman":
+		srv.SetSmoother(smoothing.NewKalmanSmoother(smoothing.DefaultKalmanConfig(), time.Second))
+	}
+	var calib *calibration.Camera
+	if calibPath != "" {
+		c, err := calibration.LoadFile(calibPath)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if len(c.Cameras) == 0 {
+			log.Fatalln("no camera in calibration file")
+		}
+		scaled := c.Cameras[0].Scaled(opts.Width, opts.Height)
+		calib = &scaled
+	}
+	if undistort {
+		if calib == nil {
+			log.Fatalln("undistort requires calibration")
+		}
+		srv.SetUndistorter(calibration.NewUndistorter(*calib))
+	}
+	var plane *ground.Plane
+	switch {
+	case floorPath != "":
+		pairs, err := ground.LoadCorrespondencesFile(floorPath)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if plane, err = ground.NewPlane(pairs); err != nil {
+			log.Fatalln(err)
+		}
+	case calib != nil && len(calib.Floor) > 0 && undistort:
+		// frames are undistorted already
+		pairs := make([]ground.Correspondence, 0, len(calib.Floor))
+		for _, pair := range calib.Floor {
+			pair.Image = calib.Undistort(pair.Image)
+			pairs = append(pairs, pair)
+		}
+		if plane, err = ground.NewPlane(pairs); err != nil {
+			log.Fatalln(err)
+		}
+	case calib != nil && len(calib.Floor) > 0:
+		if plane, err = ground.NewCameraPlane(*calib); err != nil {
+			log.Fatalln(err)
+		}
+	}
+	if plane != nil {
+		cfg := proximity.DefaultConfig()
+		cfg.Radius = distance
+		srv.SetProximity(plane, proximity.NewMonitor(cfg))
+	}
 
 	exitCh := make(chan os.Signal, 1)
 	signal.Notify(exitCh, os.Interrupt)
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"image"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/calibration"
+	"github.com/bububa/openpose/fall"
+	"github.com/bububa/openpose/ground"
+	"github.com/bububa/openpose/interpolate"
+	"github.com/bububa/openpose/proximity"
+	"github.com/bububa/openpose/rules"
+	"github.com/bububa/openpose/smoothing"
+	"github.com/bububa/openpose/swap"
+	"github.com/bububa/openpose/tracker"
+)
+
+// Estimator estimates, tracks and draws humans of camera frames
+type Estimator struct {
+	e           *openpose.PoseEstimator
+	undistorter *calibration.Undistorter
+	transform   openpose.Transform
+	tracker     *tracker.Tracker
+	engine      *rules.Engine
+	swaps       *swap.Corrector
+	fall        *fall.Detector
+	filler      *interpolate.Filler
+	smoother    *smoothing.Smoother
+	plane       *ground.Plane
+	monitor     *proximity.Monitor
+}
+
+// NewEstimator returns a new Estimator of openpose estimator
+func NewEstimator(e *openpose.PoseEstimator) *Estimator {
+	return &Estimator{
+		e:       e,
+		tracker: tracker.New(tracker.DefaultConfig()),
+	}
+}
+
+// LoadModel load openpose model
+func (s *Estimator) LoadModel() error {
+	return s.e.LoadModel()
+}
+
+// SetUndistorter set lens undistorter of frames, frames are undistorted before estimation once set
+func (s *Estimator) SetUndistorter(undistorter *calibration.Undistorter) {
+	s.undistorter = undistorter
+}
+
+// SetTransform set transform of frames such as rotation or mirroring, frames are drawn transformed
+func (s *Estimator) SetTransform(transform openpose.Transform) {
+	s.transform = transform
+}
+
+// SetRules set rules engine to evaluate on tracked humans
+func (s *Estimator) SetRules(engine *rules.Engine) {
+	s.engine = engine
+}
+
+// SetSwapCorrector set left/right swap corrector of tracked humans, only tracked humans are drawn once set
+func (s *Estimator) SetSwapCorrector(corrector *swap.Corrector) {
+	s.swaps = corrector
+}
+
+// SetFallDetector set fall detector of tracked humans
+func (s *Estimator) SetFallDetector(detector *fall.Detector) {
+	s.fall = detector
+}
+
+// SetFiller set gap filler of missing parts of tracked humans, only tracked humans are drawn once set
+func (s *Estimator) SetFiller(filler *interpolate.Filler) {
+	s.filler = filler
+}
+
+// SetSmoother set smoother of tracked humans, only tracked humans are drawn once set
+func (s *Estimator) SetSmoother(smoother *smoothing.Smoother) {
+	s.smoother = smoother
+}
+
+// SetProximity set ground plane and proximity monitor of tracked humans, distances are drawn once set
+func (s *Estimator) SetProximity(plane *ground.Plane, monitor *proximity.Monitor) {
+	s.plane = plane
+	s.monitor = monitor
+}
+
+// Draw estimates humans in img and returns the image with humans and their track ids drawn,
+// humans are returned in coordinates of img before the transform.
+// Draw updates tracking state, so each camera frame is drawn once and shared by clients through Frames
+func (s *Estimator) Draw(img image.Image) (image.Image, []openpose.Human, error) {
+	if s.undistorter != nil {
+		img = s.undistorter.Undistort(img)
+	}
+	raw := img.Bounds()
+	img = s.transform.Apply(img)
+	humans, err := s.e.Estimate(img, openpose.ModelSizeFaster)
+	if err != nil {
+		return img, nil, err
+	}
+	bounds := img.Bounds()
+	imgW, imgH := float64(bounds.Dx()), float64(bounds.Dy())
+	now := time.Now()
+	tracks, events := s.tracker.Update(humans, imgW, imgH, now)
+	for _, event := range events {
+		log.Printf("tracker: %s %s\n", event.Kind, event.Track)
+		if event.Kind != tracker.Death {
+			continue
+		}
+		if s.engine != nil {
+			s.engine.Forget(event.Track.ID)
+		}
+		if s.swaps != nil {
+			s.swaps.Forget(event.Track.ID)
+		}
+		if s.fall != nil {
+			s.fall.Forget(event.Track.ID)
+		}
+		if s.filler != nil {
+			s.filler.Forget(event.Track.ID)
+		}
+		if s.smoother != nil {
+			s.smoother.Forget(event.Track.ID)
+		}
+	}
+	if s.swaps != nil {
+		for idx, track := range tracks {
+			tracks[idx].Human, _ = s.swaps.Correct(track.ID, track.Human, imgW, imgH, now)
+		}
+	}
+	if s.filler != nil {
+		for idx, track := range tracks {
+			tracks[idx].Human = s.filler.Fill(track.ID, track.Human, imgW, imgH, now)
+		}
+	}
+	if s.smoother != nil {
+		for idx, track := range tracks {
+			tracks[idx].Human = s.smoother.Smooth(track.ID, track.Human, imgW, imgH, now)
+		}
+	}
+	if s.swaps != nil || s.filler != nil || s.smoother != nil {
+		// draw tracked humans with the track stages applied
+		humans = make([]openpose.Human, 0, len(tracks))
+		for _, track := range tracks {
+			humans = append(humans, track.Human)
+		}
+	}
+	if s.engine != nil {
+		for _, track := range tracks {
+			for _, event := range s.engine.Process(track.ID, track.Human, imgW, imgH, now) {
+				log.Printf("rules: %s\n", event)
+			}
+		}
+	}
+	if s.fall != nil {
+		for _, track := range tracks {
+			for _, event := range s.fall.Update(track.ID, track.Human, imgW, imgH, now) {
+				log.Printf("fall: %s\n", event)
+			}
+		}
+	}
+	drawn := openpose.DrawHumans(img, humans, 3)
+	if s.plane != nil && s.monitor != nil {
+		// the floor is mapped from raw frames
+		m, _ := s.transform.Matrix(raw.Size())
+		people := make([]proximity.Person, 0, len(tracks))
+		for _, track := range tracks {
+			pos, found := s.plane.Locate(s.transform.Human(track.Human, raw), float64(raw.Dx()), float64(raw.Dy()))
+			if !found {
+				continue
+			}
+			pos.Image = m.Apply(pos.Image)
+			people = append(people, proximity.Person{ID: track.ID, Position: pos})
+		}
+		pairs, events := s.monitor.Update(people, now)
+		for _, event := range events {
+			log.Printf("proximity: %s\n", event)
+		}
+		drawn = proximity.DrawDistances(drawn, people, pairs, s.monitor.Radius(), 2)
+	}
+	out := toDrawable(drawn)
+	fg := openpose.ColorFromHex(TextColor)
+	for _, track := range tracks {
+		if track.Box.Area() == 0 {
+			continue
+		}
+		drawLabel(out, bounds.Min.X+track.Box.X, bounds.Min.Y+track.Box.Y, strconv.Itoa(track.ID), fg, labelColor(track.ID))
+	}
+	return out, s.transform.Humans(humans, raw), nil
+}
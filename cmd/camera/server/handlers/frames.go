@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"image"
+	"sync"
+
+	"github.com/bububa/camera"
+
+	"github.com/bububa/openpose"
+)
+
+// Frame is a camera frame drawn by the estimator
+type Frame struct {
+	// Seq sequence number of the frame, increasing by one for each camera read
+	Seq int
+	// Image frame with humans drawn, the camera frame if estimation failed
+	Image image.Image
+	// Humans humans estimated in the frame
+	Humans []openpose.Human
+	// Err estimation error of the frame
+	Err error
+}
+
+// Frames reads camera frames and runs the estimator once per frame for all clients,
+// so tracking state sees each scene once however many clients are connected
+type Frames struct {
+	e       *Estimator
+	cam     *camera.Camera
+	frame   Frame
+	reading bool
+	mutex   sync.Mutex
+	cond    *sync.Cond
+}
+
+// NewFrames returns a new Frames of estimator and camera, frames are not drawn if estimator is nil
+func NewFrames(e *Estimator, cam *camera.Camera) *Frames {
+	f := &Frames{
+		e:   e,
+		cam: cam,
+	}
+	f.cond = sync.NewCond(&f.mutex)
+	return f
+}
+
+// Seq returns sequence number of the latest frame
+func (f *Frames) Seq() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.frame.Seq
+}
+
+// Read returns the latest frame newer than seq. If there is none, the first client reads and draws
+// the next camera frame while the others wait for it
+func (f *Frames) Read(seq int) (Frame, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for f.frame.Seq <= seq {
+		if f.reading {
+			f.cond.Wait()
+			continue
+		}
+		f.reading = true
+		f.mutex.Unlock()
+		frame, err := f.read()
+		f.mutex.Lock()
+		f.reading = false
+		f.cond.Broadcast()
+		if err != nil {
+			return Frame{}, err
+		}
+		frame.Seq = f.frame.Seq + 1
+		f.frame = frame
+	}
+	return f.frame, nil
+}
+
+// read reads and draws a camera frame
+func (f *Frames) read() (Frame, error) {
+	img, err := f.cam.Read()
+	if err != nil {
+		return Frame{}, err
+	}
+	frame := Frame{Image: img}
+	if f.e != nil {
+		if out, humans, err := f.e.Draw(img); err == nil {
+			frame.Image = out
+			frame.Humans = humans
+		} else {
+			frame.Err = err
+		}
+	}
+	return frame, nil
+}
@@ -4,20 +4,17 @@ import (
 	"log"
 	"net/http"
 
-	"github.com/bububa/camera"
 	"github.com/bububa/camera/image"
-	"github.com/bububa/openpose"
 )
 
 // JPEG handler.
 type JPEG struct {
-	e   *openpose.PoseEstimator
-	cam *camera.Camera
+	frames *Frames
 }
 
 // NewJPEG returns new JPEG handler.
-func NewJPEG(e *openpose.PoseEstimator, cam *camera.Camera) *JPEG {
-	return &JPEG{e, cam}
+func NewJPEG(frames *Frames) *JPEG {
+	return &JPEG{frames}
 }
 
 // ServeHTTP handles requests on incoming connections.
@@ -31,18 +28,13 @@ func (s *JPEG) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Add("Cache-Control", "no-store, no-cache")
 	w.Header().Add("Content-Type", "image/jpeg")
 
-	img, err := s.cam.Read()
+	frame, err := s.frames.Read(s.frames.Seq())
 	if err != nil {
 		log.Printf("jpeg: read: %v", err)
 		return
 	}
-	if s.e != nil {
-		if humans, err := s.e.Estimate(img, openpose.ModelSizeFaster); err == nil {
-			img = openpose.DrawHumans(img, humans, 3)
-		}
-	}
 
-	if err := image.NewEncoder(w).Encode(img); err != nil {
+	if err := image.NewEncoder(w).Encode(frame.Image); err != nil {
 		log.Printf("jpeg: encode: %v", err)
 		return
 	}
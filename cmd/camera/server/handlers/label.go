@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/bububa/openpose"
+)
+
+// drawLabel draws text label with background at (x, y) as the bottom left corner
+func drawLabel(img draw.Image, x int, y int, label string, fg color.Color, bg color.Color) {
+	face := basicfont.Face7x13
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(fg),
+		Face: face,
+	}
+	width := drawer.MeasureString(label).Ceil()
+	bounds := image.Rect(x-2, y-face.Ascent-2, x+width+2, y+face.Descent+2)
+	draw.Draw(img, bounds.Intersect(img.Bounds()), image.NewUniform(bg), image.Point{}, draw.Src)
+	drawer.Dot = fixed.P(x, y)
+	drawer.DrawString(label)
+}
+
+// toDrawable returns img as draw.Image, copies img if it's not drawable
+func toDrawable(img image.Image) draw.Image {
+	if out, ok := img.(draw.Image); ok {
+		return out
+	}
+	out := image.NewRGBA(img.Bounds())
+	draw.Draw(out, out.Bounds(), img, img.Bounds().Min, draw.Src)
+	return out
+}
+
+// labelColor returns label background color of id
+func labelColor(id int) color.Color {
+	c := openpose.CocoColors[id%len(openpose.CocoColors)]
+	return color.RGBA{c[0], c[1], c[2], 255}
+}
@@ -8,21 +8,18 @@ import (
 	"net/textproto"
 	"time"
 
-	"github.com/bububa/camera"
 	"github.com/bububa/camera/image"
-	"github.com/bububa/openpose"
 )
 
 // MJPEG handler.
 type MJPEG struct {
-	e     *openpose.PoseEstimator
-	cam   *camera.Camera
-	delay int
+	frames *Frames
+	delay  int
 }
 
 // NewMJPEG returns new MJPEG handler.
-func NewMJPEG(e *openpose.PoseEstimator, cam *camera.Camera, delay int) *MJPEG {
-	return &MJPEG{e, cam, delay}
+func NewMJPEG(frames *Frames, delay int) *MJPEG {
+	return &MJPEG{frames, delay}
 }
 
 // ServeHTTP handles requests on incoming connections.
@@ -40,6 +37,7 @@ func (s *MJPEG) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Add("Content-Type", fmt.Sprintf("multipart/x-mixed-replace;boundary=%s", mimeWriter.Boundary()))
 
 	cn := w.(http.CloseNotifier).CloseNotify()
+	seq := s.frames.Seq()
 
 loop:
 	for {
@@ -57,21 +55,19 @@ loop:
 				continue
 			}
 
-			img, err := s.cam.Read()
+			frame, err := s.frames.Read(seq)
 			if err != nil {
 				log.Printf("jpeg: read: %v", err)
 				return
 			}
-			if s.e != nil {
-				if humans, err := s.e.Estimate(img, openpose.ModelSizeFaster); err == nil {
-					log.Printf("found:%d\n", len(humans))
-					img = openpose.DrawHumans(img, humans, 3)
-				} else {
-					log.Println("nothing found")
-				}
+			seq = frame.Seq
+			if frame.Err == nil {
+				log.Printf("found:%d\n", len(frame.Humans))
+			} else {
+				log.Println("nothing found")
 			}
 
-			err = image.NewEncoder(partWriter).Encode(img)
+			err = image.NewEncoder(partWriter).Encode(frame.Image)
 			if err != nil {
 				log.Printf("mjpeg: encode: %v", err)
 				continue
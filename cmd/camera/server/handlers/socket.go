@@ -9,21 +9,18 @@ import (
 
 	"nhooyr.io/websocket"
 
-	"github.com/bububa/camera"
 	"github.com/bububa/camera/image"
-	"github.com/bububa/openpose"
 )
 
 // Socket handler.
 type Socket struct {
-	e     *openpose.PoseEstimator
-	cam   *camera.Camera
-	delay int
+	frames *Frames
+	delay  int
 }
 
 // NewSocket returns new socket handler.
-func NewSocket(e *openpose.PoseEstimator, cam *camera.Camera, delay int) *Socket {
-	return &Socket{e, cam, delay}
+func NewSocket(frames *Frames, delay int) *Socket {
+	return &Socket{frames, delay}
 }
 
 // ServeHTTP handles requests on incoming connections.
@@ -35,22 +32,19 @@ func (s *Socket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.Background()
+	seq := s.frames.Seq()
 
 	for {
-		img, err := s.cam.Read()
+		frame, err := s.frames.Read(seq)
 		if err != nil {
 			log.Printf("jpeg: read: %v", err)
 			return
 		}
-		if s.e != nil {
-			if humans, err := s.e.Estimate(img, openpose.ModelSizeFaster); err == nil {
-				img = openpose.DrawHumans(img, humans, 3)
-			}
-		}
+		seq = frame.Seq
 
 		w := new(bytes.Buffer)
 
-		err = image.NewEncoder(w).Encode(img)
+		err = image.NewEncoder(w).Encode(frame.Image)
 		if err != nil {
 			log.Printf("socket: encode: %v", err)
 			continue
@@ -8,13 +8,21 @@ import (
 
 	"github.com/bububa/camera"
 	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/calibration"
 	"github.com/bububa/openpose/cmd/camera/server/handlers"
+	"github.com/bububa/openpose/fall"
+	"github.com/bububa/openpose/ground"
+	"github.com/bububa/openpose/interpolate"
+	"github.com/bububa/openpose/proximity"
+	"github.com/bububa/openpose/rules"
+	"github.com/bububa/openpose/smoothing"
+	"github.com/bububa/openpose/swap"
 )
 
 // Server represents server
 type Server struct {
 	srv         *http.Server
-	estimator   *openpose.PoseEstimator
+	estimator   *handlers.Estimator
 	cam         *camera.Camera
 	delay       int
 	bind        string
@@ -26,7 +34,7 @@ type Server struct {
 func New(bind string, estimator *openpose.PoseEstimator, cam *camera.Camera) *Server {
 	s := &Server{
 		srv:         new(http.Server),
-		estimator:   estimator,
+		estimator:   handlers.NewEstimator(estimator),
 		cam:         cam,
 		bind:        bind,
 		frameWidth:  FrameWidth,
@@ -47,6 +55,46 @@ func (s *Server) SetDelay(delay int) {
 	s.delay = delay
 }
 
+// SetUndistorter set lens undistorter of frames
+func (s *Server) SetUndistorter(undistorter *calibration.Undistorter) {
+	s.estimator.SetUndistorter(undistorter)
+}
+
+// SetTransform set transform of frames such as rotation or mirroring
+func (s *Server) SetTransform(transform openpose.Transform) {
+	s.estimator.SetTransform(transform)
+}
+
+// SetRules set rules to evaluate on estimated humans, events are logged
+func (s *Server) SetRules(rs []*rules.Rule) {
+	s.estimator.SetRules(rules.NewEngine(rs...))
+}
+
+// SetSwapCorrector set left/right swap corrector of tracked humans
+func (s *Server) SetSwapCorrector(corrector *swap.Corrector) {
+	s.estimator.SetSwapCorrector(corrector)
+}
+
+// SetFallDetector set fall detector of tracked humans, falls are logged
+func (s *Server) SetFallDetector(detector *fall.Detector) {
+	s.estimator.SetFallDetector(detector)
+}
+
+// SetFiller set gap filler of missing keypoints of tracked humans
+func (s *Server) SetFiller(filler *interpolate.Filler) {
+	s.estimator.SetFiller(filler)
+}
+
+// SetSmoother set smoother of tracked keypoints
+func (s *Server) SetSmoother(smoother *smoothing.Smoother) {
+	s.estimator.SetSmoother(smoother)
+}
+
+// SetProximity set ground plane and proximity monitor of tracked humans, alerts are logged
+func (s *Server) SetProximity(plane *ground.Plane, monitor *proximity.Monitor) {
+	s.estimator.SetProximity(plane, monitor)
+}
+
 // Start to start server
 func (s *Server) Start() error {
 	s.estimator.LoadModel()
@@ -70,9 +118,11 @@ func (s *Server) Shutdown(ctx context.Context) error {
 func (s *Server) ListenAndServe() error {
 	http.Handle("/html/webgl", handlers.NewHTML(s.frameWidth, s.frameHeight, true))
 	http.Handle("/html", handlers.NewHTML(s.frameWidth, s.frameHeight, false))
-	http.Handle("/jpeg", handlers.NewJPEG(s.estimator, s.cam))
-	http.Handle("/mjpeg", handlers.NewMJPEG(s.estimator, s.cam, s.delay))
-	http.Handle("/socket", handlers.NewSocket(s.estimator, s.cam, s.delay))
+	// clients share the frames, so each camera frame is estimated and tracked once
+	frames := handlers.NewFrames(s.estimator, s.cam)
+	http.Handle("/jpeg", handlers.NewJPEG(frames))
+	http.Handle("/mjpeg", handlers.NewMJPEG(frames, s.delay))
+	http.Handle("/socket", handlers.NewSocket(frames, s.delay))
 
 	http.HandleFunc("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/evaluate"
+)
+
+var (
+	modelPath       string
+	modelType       string
+	modelSize       string
+	sharpenSigma    float64
+	imageDir        string
+	annotationsPath string
+	detectionsPath  string
+	outputPath      string
+)
+
+var modelSizes = map[string]openpose.ModelSize{
+	"best":    openpose.ModelSizeBest,
+	"better":  openpose.ModelSizeBetter,
+	"cmu":     openpose.ModelSizeCMU,
+	"default": openpose.ModelSizeDefault,
+	"faster":  openpose.ModelSizeFaster,
+	"fatest":  openpose.ModelSizeFatest,
+}
+
+func init() {
+	flag.StringVar(&modelPath, "model", "", "set openpose model path")
+	flag.StringVar(&modelType, "model-type", "mobilenet", "set openpose model type")
+	flag.StringVar(&modelSize, "model-size", "default", "set model size, best|better|cmu|default|faster|fatest")
+	flag.Float64Var(&sharpenSigma, "sharpen", openpose.DefaultSharpenSigma, "set sharpen sigma for image preprocessing")
+	flag.StringVar(&imageDir, "images", "", "set image folder")
+	flag.StringVar(&annotationsPath, "annotations", "", "set COCO person_keypoints ground-truth json path")
+	flag.StringVar(&detectionsPath, "detections", "", "set detections json path, reused if exists otherwise written after estimation")
+	flag.StringVar(&outputPath, "output", "", "set evaluation result json path")
+}
+
+func main() {
+	flag.Parse()
+	if annotationsPath == "" {
+		log.Fatalln("annotations is required")
+	}
+	ds, err := evaluate.LoadDatasetFile(annotationsPath)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	dts, err := loadOrDetect(ds)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	result := evaluate.Evaluate(ds, dts, evaluate.DefaultParams())
+	if err := result.WriteTable(os.Stdout); err != nil {
+		log.Fatalln(err)
+	}
+	if outputPath == "" {
+		return
+	}
+	fn, err := os.Create(outputPath)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer fn.Close()
+	encoder := json.NewEncoder(fn)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// loadOrDetect loads cached detections so the evaluation could be reproduced offline, otherwise runs the estimator
+func loadOrDetect(ds *evaluate.Dataset) ([]evaluate.Detection, error) {
+	if detectionsPath != "" {
+		if _, err := os.Stat(detectionsPath); err == nil {
+			log.Printf("loading detections from %s\n", detectionsPath)
+			return evaluate.LoadDetectionsFile(detectionsPath)
+		}
+	}
+	if modelPath == "" || imageDir == "" {
+		return nil, errors.New("model and images are required without cached detections")
+	}
+	size, found := modelSizes[modelSize]
+	if !found {
+		return nil, errors.New("invalid model size")
+	}
+	mt := openpose.MobileNet
+	if modelType == "cmu" {
+		mt = openpose.CMU
+	}
+	estimator := openpose.NewPoseEstimator(modelPath, mt)
+	estimator.SetSharpenSigma(sharpenSigma)
+	log.Printf("estimating %d images\n", len(ds.Images))
+	dts, err := evaluate.Detect(estimator, ds, imageDir, size)
+	if err != nil {
+		return nil, err
+	}
+	if detectionsPath != "" {
+		fn, err := os.Create(detectionsPath)
+		if err != nil {
+			return nil, err
+		}
+		defer fn.Close()
+		if err := evaluate.WriteDetections(fn, dts); err != nil {
+			return nil, err
+		}
+	}
+	return dts, nil
+}
@@ -0,0 +1,238 @@
+package compare
+
+import (
+	"math"
+	"sort"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/dtw"
+	"github.com/bububa/openpose/similarity"
+)
+
+// Config represents comparison config
+type Config struct {
+	// Band Sakoe-Chiba band of dtw in frames, 0 disables it
+	Band int
+	// DistanceTolerance normalized keypoint distance scoring exp(-1/2), 0 takes the default
+	DistanceTolerance float64
+	// AngleTolerance limb direction difference in degrees scoring exp(-1/2), 0 takes the default
+	AngleTolerance float64
+	// SegmentLength aligned frames of a segment
+	SegmentLength int
+	// WorstSegments max worst matching segments reported
+	WorstSegments int
+}
+
+// DefaultConfig returns default comparison config
+func DefaultConfig() Config {
+	return Config{
+		DistanceTolerance: 0.25,
+		AngleTolerance:    30,
+		SegmentLength:     15,
+		WorstSegments:     3,
+	}
+}
+
+// FrameScore represents similarity of an aligned user and reference frame pair
+type FrameScore struct {
+	// User user frame index
+	User int
+	// Reference reference frame index
+	Reference int
+	// Score keypoint similarity in [0, 1]
+	Score float64
+	// Limbs limb direction similarity in [0, 1] of limbs visible in both frames
+	Limbs map[openpose.Limb]float64
+}
+
+// Segment represents a range of aligned frames
+type Segment struct {
+	// Start first index of timeline
+	Start int
+	// End last index of timeline
+	End int
+	// UserStart first user frame
+	UserStart int
+	// UserEnd last user frame
+	UserEnd int
+	// ReferenceStart first reference frame
+	ReferenceStart int
+	// ReferenceEnd last reference frame
+	ReferenceEnd int
+	// Score mean frame score
+	Score float64
+	// WorstLimb limb of the lowest mean score in segment
+	WorstLimb openpose.Limb
+}
+
+// Result represents comparison result
+type Result struct {
+	// Score overall score in [0, 100]
+	Score float64
+	// Timeline frame scores along the warping path
+	Timeline []FrameScore
+	// Limbs mean similarity of each limb
+	Limbs map[openpose.Limb]float64
+	// Worst worst matching segments ordered by score
+	Worst []Segment
+}
+
+// Compare aligns user humans with reference humans, both in pixel space compared to img size (w, h), and scores them
+func Compare(user []openpose.Human, reference []openpose.Human, imgW float64, imgH float64, cfg Config) Result {
+	var ret Result
+	if len(user) == 0 || len(reference) == 0 {
+		return ret
+	}
+	if cfg.DistanceTolerance <= 0 {
+		cfg.DistanceTolerance = DefaultConfig().DistanceTolerance
+	}
+	if cfg.AngleTolerance <= 0 {
+		cfg.AngleTolerance = DefaultConfig().AngleTolerance
+	}
+	distances := make([][]float64, len(user))
+	for i := range user {
+		distances[i] = make([]float64, len(reference))
+		for j := range reference {
+			distances[i][j] = math.Inf(1)
+		}
+	}
+	_, path := dtw.Align(len(user), len(reference), func(i int, j int) float64 {
+		d, ok := similarity.NormalizedDistance(user[i], reference[j], imgW, imgH)
+		if !ok {
+			// nothing in common, as far as unrelated unit RMS poses
+			d = math.Sqrt2
+		}
+		distances[i][j] = d
+		return d
+	}, cfg.Band)
+	if len(path) == 0 {
+		return ret
+	}
+	ret.Timeline = make([]FrameScore, 0, len(path))
+	var (
+		total     float64
+		limbSum   = make(map[openpose.Limb]float64, len(openpose.Limbs))
+		limbCount = make(map[openpose.Limb]float64, len(openpose.Limbs))
+	)
+	for _, step := range path {
+		d := distances[step.I][step.J] / cfg.DistanceTolerance
+		score := FrameScore{
+			User:      step.I,
+			Reference: step.J,
+			Score:     math.Exp(-d * d / 2),
+			Limbs:     limbScores(user[step.I], reference[step.J], imgW, imgH, cfg.AngleTolerance),
+		}
+		total += score.Score
+		for limb, v := range score.Limbs {
+			limbSum[limb] += v
+			limbCount[limb]++
+		}
+		ret.Timeline = append(ret.Timeline, score)
+	}
+	ret.Score = 100 * total / float64(len(path))
+	ret.Limbs = make(map[openpose.Limb]float64, len(limbSum))
+	for limb, sum := range limbSum {
+		ret.Limbs[limb] = sum / limbCount[limb]
+	}
+	ret.Worst = worstSegments(ret.Timeline, cfg.SegmentLength, cfg.WorstSegments)
+	return ret
+}
+
+// limbScores returns direction similarity of limbs visible in both a and b
+func limbScores(a openpose.Human, b openpose.Human, imgW float64, imgH float64, tolerance float64) map[openpose.Limb]float64 {
+	ret := make(map[openpose.Limb]float64, len(openpose.Limbs))
+	for _, limb := range openpose.Limbs {
+		va, foundA := limbVector(a, limb, imgW, imgH)
+		vb, foundB := limbVector(b, limb, imgW, imgH)
+		if !foundA || !foundB {
+			continue
+		}
+		angle, ok := openpose.VectorAngle(va, vb)
+		if !ok {
+			continue
+		}
+		d := angle / tolerance
+		ret[limb] = math.Exp(-d * d / 2)
+	}
+	return ret
+}
+
+// limbVector returns limb vector in pixels
+func limbVector(h openpose.Human, limb openpose.Limb, imgW float64, imgH float64) (openpose.Point, bool) {
+	a, foundA := h.PixelCenter([]openpose.CocoPart{limb[0]}, imgW, imgH)
+	b, foundB := h.PixelCenter([]openpose.CocoPart{limb[1]}, imgW, imgH)
+	if !foundA || !foundB {
+		return openpose.ZP, false
+	}
+	return b.Sub(a), true
+}
+
+// worstSegments returns up to count non overlapping segments of length with the lowest mean score
+func worstSegments(timeline []FrameScore, length int, count int) []Segment {
+	if length <= 0 || count <= 0 || len(timeline) == 0 {
+		return nil
+	}
+	if length > len(timeline) {
+		length = len(timeline)
+	}
+	candidates := make([]Segment, 0, len(timeline)-length+1)
+	var sum float64
+	for idx, frame := range timeline {
+		sum += frame.Score
+		if idx >= length {
+			sum -= timeline[idx-length].Score
+		}
+		if idx < length-1 {
+			continue
+		}
+		candidates = append(candidates, Segment{Start: idx - length + 1, End: idx, Score: sum / float64(length)})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score < candidates[j].Score })
+	var ret []Segment
+	for _, candidate := range candidates {
+		overlapped := false
+		for _, segment := range ret {
+			if candidate.Start <= segment.End && segment.Start <= candidate.End {
+				overlapped = true
+				break
+			}
+		}
+		if overlapped {
+			continue
+		}
+		ret = append(ret, fillSegment(timeline, candidate))
+		if len(ret) == count {
+			break
+		}
+	}
+	return ret
+}
+
+// fillSegment sets frame ranges and worst limb of segment
+func fillSegment(timeline []FrameScore, segment Segment) Segment {
+	first, last := timeline[segment.Start], timeline[segment.End]
+	segment.UserStart, segment.UserEnd = first.User, last.User
+	segment.ReferenceStart, segment.ReferenceEnd = first.Reference, last.Reference
+	var (
+		sums   = make(map[openpose.Limb]float64, len(openpose.Limbs))
+		counts = make(map[openpose.Limb]float64, len(openpose.Limbs))
+		worst  = math.Inf(1)
+	)
+	for _, frame := range timeline[segment.Start : segment.End+1] {
+		for limb, v := range frame.Limbs {
+			sums[limb] += v
+			counts[limb]++
+		}
+	}
+	// iterate limbs in order for a stable result
+	for _, limb := range openpose.Limbs {
+		if counts[limb] == 0 {
+			continue
+		}
+		if mean := sums[limb] / counts[limb]; mean < worst {
+			worst = mean
+			segment.WorstLimb = limb
+		}
+	}
+	return segment
+}
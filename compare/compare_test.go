@@ -0,0 +1,78 @@
+package compare
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/internal/posetest"
+)
+
+// newHuman returns a standing human in a 640x480 image raising the right arm by angle in degrees
+func newHuman(angle float64) openpose.Human {
+	rad := angle * math.Pi / 180
+	arm := openpose.Pt(-math.Sin(rad), math.Cos(rad))
+	shoulder := openpose.Pt(290, 100)
+	elbow := shoulder.Add(arm.Mul(60))
+	wrist := elbow.Add(arm.Mul(60))
+	points := map[openpose.CocoPart]openpose.Point{
+		openpose.CocoPartNose:      openpose.Pt(320, 60),
+		openpose.CocoPartNeck:      openpose.Pt(320, 100),
+		openpose.CocoPartRShoulder: shoulder,
+		openpose.CocoPartLShoulder: openpose.Pt(350, 100),
+		openpose.CocoPartRElbow:    elbow,
+		openpose.CocoPartLElbow:    openpose.Pt(360, 160),
+		openpose.CocoPartRWrist:    wrist,
+		openpose.CocoPartLWrist:    openpose.Pt(365, 220),
+		openpose.CocoPartRHip:      openpose.Pt(300, 220),
+		openpose.CocoPartLHip:      openpose.Pt(340, 220),
+		openpose.CocoPartRKnee:     openpose.Pt(300, 320),
+		openpose.CocoPartLKnee:     openpose.Pt(340, 320),
+	}
+	return posetest.Human(points, 640, 480)
+}
+
+// performance returns frames raising the right arm to 180 degrees and back, wrong drops the arm to 45 degrees in the middle
+func performance(frames int, wrong bool) []openpose.Human {
+	ret := make([]openpose.Human, frames)
+	for idx := range ret {
+		u := float64(idx) / float64(frames-1)
+		angle := 180 * math.Sin(math.Pi*u)
+		if wrong && u > 0.4 && u < 0.6 {
+			angle = 45
+		}
+		ret[idx] = newHuman(angle)
+	}
+	return ret
+}
+
+func TestCompare(t *testing.T) {
+	cfg := DefaultConfig()
+	reference := performance(40, false)
+	// same moves, slower
+	result := Compare(performance(60, false), reference, 640, 480, cfg)
+	assert.Greater(t, result.Score, 95.0)
+	first, last := result.Timeline[0], result.Timeline[len(result.Timeline)-1]
+	assert.Equal(t, [2]int{0, 0}, [2]int{first.User, first.Reference})
+	assert.Equal(t, [2]int{59, 39}, [2]int{last.User, last.Reference})
+	assert.InDelta(t, 1, result.Limbs[openpose.LimbRForearm], 0.05)
+
+	// zero tolerances take the defaults
+	zero := Compare(performance(60, false), reference, 640, 480, Config{})
+	assert.False(t, math.IsNaN(zero.Score))
+	assert.InDelta(t, result.Score, zero.Score, 1e-9)
+	assert.Empty(t, zero.Worst)
+
+	wrong := Compare(performance(60, true), reference, 640, 480, cfg)
+	assert.Less(t, wrong.Score, 90.0)
+	if assert.NotEmpty(t, wrong.Worst) {
+		worst := wrong.Worst[0]
+		// the wrong moves are around the middle of the user performance
+		assert.True(t, worst.UserStart <= 30 && worst.UserEnd >= 30, "%+v", worst)
+		assert.Less(t, worst.Score, 0.5)
+		assert.Contains(t, []openpose.Limb{openpose.LimbRUpperArm, openpose.LimbRForearm}, worst.WorstLimb)
+	}
+	assert.Len(t, wrong.Worst, cfg.WorstSegments)
+}
@@ -0,0 +1,2 @@
+// Package compare compares pose sequences with a reference performance aligned by dynamic time warping
+package compare
@@ -1,5 +1,10 @@
 package openpose
 
+import (
+	"fmt"
+	"strings"
+)
+
 const TotalBodyParts = 18
 
 // CocoPart represents body parts
@@ -46,6 +51,77 @@ const (
 	CocoPartBackground
 )
 
+// CocoPartNames represents names of CocoParts
+var CocoPartNames = map[CocoPart]string{
+	CocoPartNose:       "Nose",
+	CocoPartNeck:       "Neck",
+	CocoPartRShoulder:  "RShoulder",
+	CocoPartRElbow:     "RElbow",
+	CocoPartRWrist:     "RWrist",
+	CocoPartLShoulder:  "LShoulder",
+	CocoPartLElbow:     "LElbow",
+	CocoPartLWrist:     "LWrist",
+	CocoPartRHip:       "RHip",
+	CocoPartRKnee:      "RKnee",
+	CocoPartRAnkle:     "RAnkle",
+	CocoPartLHip:       "LHip",
+	CocoPartLKnee:      "LKnee",
+	CocoPartLAnkle:     "LAnkle",
+	CocoPartREye:       "REye",
+	CocoPartLEye:       "LEye",
+	CocoPartREar:       "REar",
+	CocoPartLEar:       "LEar",
+	CocoPartBackground: "Background",
+}
+
+// String returns CocoPart name
+func (p CocoPart) String() string {
+	if name, found := CocoPartNames[p]; found {
+		return name
+	}
+	return fmt.Sprintf("CocoPart(%d)", int(p))
+}
+
+// ParseCocoPart returns CocoPart of name, name is case insensitive
+func ParseCocoPart(name string) (CocoPart, error) {
+	for part, partName := range CocoPartNames {
+		if strings.EqualFold(partName, name) {
+			return part, nil
+		}
+	}
+	return CocoPartBackground, fmt.Errorf("unknown coco part: %s", name)
+}
+
+// CocoPartPairsLR represents right and left part pairs
+var CocoPartPairsLR = [][2]CocoPart{
+	{CocoPartRShoulder, CocoPartLShoulder},
+	{CocoPartRElbow, CocoPartLElbow},
+	{CocoPartRWrist, CocoPartLWrist},
+	{CocoPartRHip, CocoPartLHip},
+	{CocoPartRKnee, CocoPartLKnee},
+	{CocoPartRAnkle, CocoPartLAnkle},
+	{CocoPartREye, CocoPartLEye},
+	{CocoPartREar, CocoPartLEar},
+}
+
+// cocoPartMirror maps left and right parts to the part of the opposite side, built from CocoPartPairsLR
+var cocoPartMirror = func() map[CocoPart]CocoPart {
+	ret := make(map[CocoPart]CocoPart, len(CocoPartPairsLR)*2)
+	for _, pair := range CocoPartPairsLR {
+		ret[pair[0]] = pair[1]
+		ret[pair[1]] = pair[0]
+	}
+	return ret
+}()
+
+// Mirror returns the part of the opposite side of the body, parts on the body center return themselves
+func (p CocoPart) Mirror() CocoPart {
+	if mirror, found := cocoPartMirror[p]; found {
+		return mirror
+	}
+	return p
+}
+
 // MPIIPart MPII human parts
 type MPIIPart int
 
@@ -158,6 +234,35 @@ var CoordPartsMap = map[CocoPart]struct{}{
 	CocoPartLEar:      {},
 }
 
+// TorsoParts represents CocoParts of torso
+var TorsoParts = []CocoPart{
+	CocoPartNeck,
+	CocoPartRShoulder,
+	CocoPartLShoulder,
+	CocoPartRHip,
+	CocoPartLHip,
+}
+
+// LowerBodyParts represents CocoParts of lower body
+var LowerBodyParts = []CocoPart{
+	CocoPartRHip,
+	CocoPartRKnee,
+	CocoPartRAnkle,
+	CocoPartLHip,
+	CocoPartLKnee,
+	CocoPartLAnkle,
+}
+
+// Side represents body side
+type Side int
+
+const (
+	// SideRight right side of body
+	SideRight Side = iota
+	// SideLeft left side of body
+	SideLeft
+)
+
 const (
 	ThresholdPartConfidence float32 = 0.3
 	InterThreashold         float32 = 0.1
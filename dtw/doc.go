@@ -0,0 +1,2 @@
+// Package dtw dynamic time warping of sequences
+package dtw
@@ -0,0 +1,122 @@
+package dtw
+
+import (
+	"math"
+)
+
+// Step represents a matched index pair of sequence a and b
+type Step struct {
+	I int
+	J int
+}
+
+// Path represents warping path from (0, 0) to (n-1, m-1)
+type Path []Step
+
+// CostFunc returns the cost of matching item i of sequence a with item j of sequence b
+type CostFunc func(i int, j int) float64
+
+// Align aligns sequence a of n items and sequence b of m items, returns the accumulated cost and the warping path.
+// band limits |i*m/n - j| to a Sakoe-Chiba band, 0 disables it. Cost is +Inf if no path fits the band
+func Align(n int, m int, cost CostFunc, band int) (float64, Path) {
+	if n == 0 || m == 0 {
+		return math.Inf(1), nil
+	}
+	acc := make([][]float64, n)
+	for i := range acc {
+		acc[i] = make([]float64, m)
+		for j := range acc[i] {
+			acc[i][j] = math.Inf(1)
+		}
+	}
+	for i := 0; i < n; i++ {
+		from, to := 0, m-1
+		if band > 0 {
+			center := i * m / n
+			from, to = maxInt(0, center-band), minInt(m-1, center+band)
+		}
+		for j := from; j <= to; j++ {
+			best := math.Inf(1)
+			switch {
+			case i == 0 && j == 0:
+				best = 0
+			case i == 0:
+				best = acc[0][j-1]
+			case j == 0:
+				best = acc[i-1][0]
+			default:
+				best = math.Min(acc[i-1][j-1], math.Min(acc[i-1][j], acc[i][j-1]))
+			}
+			if math.IsInf(best, 1) {
+				continue
+			}
+			acc[i][j] = best + cost(i, j)
+		}
+	}
+	total := acc[n-1][m-1]
+	if math.IsInf(total, 1) {
+		return total, nil
+	}
+	return total, backtrack(acc)
+}
+
+// Distance returns the accumulated cost of Align normalized by the path length
+func Distance(n int, m int, cost CostFunc, band int) float64 {
+	total, path := Align(n, m, cost, band)
+	if len(path) == 0 {
+		return total
+	}
+	return total / float64(len(path))
+}
+
+// backtrack returns the path of accumulated cost matrix
+func backtrack(acc [][]float64) Path {
+	i, j := len(acc)-1, len(acc[0])-1
+	path := Path{{i, j}}
+	for i > 0 || j > 0 {
+		switch {
+		case i == 0:
+			j--
+		case j == 0:
+			i--
+		default:
+			diag, up, left := acc[i-1][j-1], acc[i-1][j], acc[i][j-1]
+			if diag <= up && diag <= left {
+				i, j = i-1, j-1
+			} else if up <= left {
+				i--
+			} else {
+				j--
+			}
+		}
+		path = append(path, Step{i, j})
+	}
+	for l, r := 0, len(path)-1; l < r; l, r = l+1, r-1 {
+		path[l], path[r] = path[r], path[l]
+	}
+	return path
+}
+
+// Euclidean returns euclidean distance of vector a and b of the same length
+func Euclidean(a []float64, b []float64) float64 {
+	var sum float64
+	for idx := range a {
+		d := a[idx] - b[idx]
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,28 @@
+package dtw
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlign(t *testing.T) {
+	a := []float64{0, 1, 2, 3, 2, 1, 0}
+	// b is a slowed down a
+	b := []float64{0, 0, 1, 1, 2, 2, 3, 3, 2, 1, 0}
+	cost := func(i int, j int) float64 { return math.Abs(a[i] - b[j]) }
+	total, path := Align(len(a), len(b), cost, 0)
+	assert.Equal(t, 0.0, total)
+	assert.Equal(t, Step{0, 0}, path[0])
+	assert.Equal(t, Step{len(a) - 1, len(b) - 1}, path[len(path)-1])
+	for idx := 1; idx < len(path); idx++ {
+		di, dj := path[idx].I-path[idx-1].I, path[idx].J-path[idx-1].J
+		assert.True(t, di >= 0 && di <= 1 && dj >= 0 && dj <= 1 && di+dj > 0)
+	}
+	// a narrow band cannot absorb the warping
+	narrow, _ := Align(len(a), len(b), cost, 1)
+	assert.Greater(t, narrow, 0.0)
+	// normalized by path length
+	assert.InDelta(t, 1, Distance(3, 5, func(i int, j int) float64 { return 1 }, 0), 1e-9)
+}
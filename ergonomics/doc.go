@@ -0,0 +1,2 @@
+// Package ergonomics RULA style ergonomic posture scoring from 2D keypoints
+package ergonomics
@@ -0,0 +1,243 @@
+package ergonomics
+
+import (
+	"math"
+
+	"github.com/bububa/openpose"
+)
+
+// Component represents a scored body segment
+type Component string
+
+const (
+	// RightUpperArm right upper arm flexion
+	RightUpperArm Component = "right_upper_arm"
+	// RightLowerArm right elbow flexion
+	RightLowerArm Component = "right_lower_arm"
+	// LeftUpperArm left upper arm flexion
+	LeftUpperArm Component = "left_upper_arm"
+	// LeftLowerArm left elbow flexion
+	LeftLowerArm Component = "left_lower_arm"
+	// Neck neck flexion, negative for extension
+	Neck Component = "neck"
+	// Trunk trunk flexion
+	Trunk Component = "trunk"
+	// Legs knee angle difference of both legs
+	Legs Component = "legs"
+)
+
+// Components represents all scored components
+var Components = []Component{RightUpperArm, RightLowerArm, LeftUpperArm, LeftLowerArm, Neck, Trunk, Legs}
+
+// Config represents scores which are not observable from keypoints
+type Config struct {
+	// WristScore RULA wrist score 1 to 4
+	WristScore int `json:"wrist_score"`
+	// WristTwistScore RULA wrist twist score 1 to 2
+	WristTwistScore int `json:"wrist_twist_score"`
+	// MuscleUseScore 1 for static or repeated postures, 0 otherwise
+	MuscleUseScore int `json:"muscle_use_score"`
+	// ForceScore RULA force and load score 0 to 3
+	ForceScore int `json:"force_score"`
+	// UnbalancedLegsAngle min knee angle difference of unbalanced legs
+	UnbalancedLegsAngle float64 `json:"unbalanced_legs_angle"`
+}
+
+// DefaultConfig returns default config
+func DefaultConfig() Config {
+	return Config{
+		WristScore:          1,
+		WristTwistScore:     1,
+		UnbalancedLegsAngle: 30,
+	}
+}
+
+// ComponentScore represents angle and score of a component
+type ComponentScore struct {
+	// Angle in degrees
+	Angle float64 `json:"angle"`
+	// Score RULA score, the minimum score if not visible
+	Score int `json:"score"`
+	// Visible reports the segment is visible
+	Visible bool `json:"visible"`
+}
+
+// Assessment represents RULA style assessment of a posture
+type Assessment struct {
+	// Components component scores
+	Components map[Component]ComponentScore `json:"components"`
+	// ScoreA wrist and arm score of the worse arm, including muscle use and force
+	ScoreA int `json:"score_a"`
+	// ScoreB neck, trunk and leg score, including muscle use and force
+	ScoreB int `json:"score_b"`
+	// Score grand score 1 to 7
+	Score int `json:"score"`
+	// ActionLevel 1 acceptable, 2 investigate further, 3 change soon, 4 change immediately
+	ActionLevel int `json:"action_level"`
+	// Missing components not visible
+	Missing []Component `json:"missing,omitempty"`
+}
+
+// Valid returns any component is visible
+func (a Assessment) Valid() bool {
+	for _, score := range a.Components {
+		if score.Visible {
+			return true
+		}
+	}
+	return false
+}
+
+// Assess scores posture of human in pixel space compared to img size (w, h).
+// Angles are measured in the image plane, a side view is expected
+func Assess(h openpose.Human, imgW float64, imgH float64, cfg Config) Assessment {
+	ret := Assessment{Components: make(map[Component]ComponentScore, len(Components))}
+	ret.Components[RightUpperArm] = upperArm(h, openpose.CocoPartRShoulder, imgW, imgH)
+	ret.Components[RightLowerArm] = lowerArm(h, openpose.CocoPartRElbow, imgW, imgH)
+	ret.Components[LeftUpperArm] = upperArm(h, openpose.CocoPartLShoulder, imgW, imgH)
+	ret.Components[LeftLowerArm] = lowerArm(h, openpose.CocoPartLElbow, imgW, imgH)
+	ret.Components[Neck] = neck(h, imgW, imgH)
+	ret.Components[Trunk] = trunk(h, imgW, imgH)
+	ret.Components[Legs] = legs(h, imgW, imgH, cfg.UnbalancedLegsAngle)
+	for _, component := range Components {
+		if !ret.Components[component].Visible {
+			ret.Missing = append(ret.Missing, component)
+		}
+	}
+	extra := cfg.MuscleUseScore + cfg.ForceScore
+	right := lookupA(ret.Components[RightUpperArm].Score, ret.Components[RightLowerArm].Score, cfg.WristScore, cfg.WristTwistScore)
+	left := lookupA(ret.Components[LeftUpperArm].Score, ret.Components[LeftLowerArm].Score, cfg.WristScore, cfg.WristTwistScore)
+	if left > right {
+		right = left
+	}
+	ret.ScoreA = right + extra
+	ret.ScoreB = lookupB(ret.Components[Neck].Score, ret.Components[Trunk].Score, ret.Components[Legs].Score) + extra
+	ret.Score = lookupC(ret.ScoreA, ret.ScoreB)
+	ret.ActionLevel = actionLevel(ret.Score)
+	return ret
+}
+
+// upperArm scores the angle between trunk and upper arm at shoulder, extension and flexion are not distinguished
+func upperArm(h openpose.Human, shoulder openpose.CocoPart, imgW float64, imgH float64) ComponentScore {
+	angle, ok := h.JointAngle(shoulder, imgW, imgH)
+	if !ok {
+		return ComponentScore{Score: 1}
+	}
+	ret := ComponentScore{Angle: angle, Visible: true}
+	switch {
+	case angle <= 20:
+		ret.Score = 1
+	case angle <= 45:
+		ret.Score = 2
+	case angle <= 90:
+		ret.Score = 3
+	default:
+		ret.Score = 4
+	}
+	return ret
+}
+
+// lowerArm scores elbow flexion
+func lowerArm(h openpose.Human, elbow openpose.CocoPart, imgW float64, imgH float64) ComponentScore {
+	angle, ok := h.JointAngle(elbow, imgW, imgH)
+	if !ok {
+		return ComponentScore{Score: 1}
+	}
+	flexion := 180 - angle
+	ret := ComponentScore{Angle: flexion, Score: 2, Visible: true}
+	if flexion >= 60 && flexion <= 100 {
+		ret.Score = 1
+	}
+	return ret
+}
+
+// neck scores the angle between trunk and neck to head vector, head is the ears or the nose.
+// Extension is detected if the facing direction is known from nose and ears
+func neck(h openpose.Human, imgW float64, imgH float64) ComponentScore {
+	neckPoint, foundNeck := h.PixelCenter([]openpose.CocoPart{openpose.CocoPartNeck}, imgW, imgH)
+	hip, foundHip := h.PixelCenter([]openpose.CocoPart{openpose.CocoPartRHip, openpose.CocoPartLHip}, imgW, imgH)
+	ears, foundEars := h.PixelCenter([]openpose.CocoPart{openpose.CocoPartREar, openpose.CocoPartLEar}, imgW, imgH)
+	nose, foundNose := h.PixelCenter([]openpose.CocoPart{openpose.CocoPartNose}, imgW, imgH)
+	head, foundHead := ears, foundEars
+	if !foundHead {
+		head, foundHead = nose, foundNose
+	}
+	if !foundNeck || !foundHip || !foundHead {
+		return ComponentScore{Score: 1}
+	}
+	up, forward := neckPoint.Sub(hip), head.Sub(neckPoint)
+	angle, ok := openpose.VectorAngle(up, forward)
+	if !ok {
+		return ComponentScore{Score: 1}
+	}
+	if foundNose && foundEars {
+		// image y axis points down, positive cross product turns to the right of the image
+		facing := nose.X - ears.X
+		if cross := up.X*forward.Y - up.Y*forward.X; facing*cross < 0 {
+			angle = -angle
+		}
+	}
+	ret := ComponentScore{Angle: angle, Visible: true}
+	switch {
+	case angle < 0:
+		ret.Score = 4
+	case angle <= 10:
+		ret.Score = 1
+	case angle <= 20:
+		ret.Score = 2
+	default:
+		ret.Score = 3
+	}
+	// side bending is visible as head roll facing the camera
+	if pose := h.HeadPose(imgW, imgH); pose.Facing == openpose.FacingCamera && pose.RollValid && math.Abs(pose.Roll) > 20 {
+		ret.Score++
+	}
+	return ret
+}
+
+// trunk scores trunk inclination to vertical
+func trunk(h openpose.Human, imgW float64, imgH float64) ComponentScore {
+	angle, ok := h.TrunkInclination(imgW, imgH)
+	if !ok {
+		return ComponentScore{Score: 1}
+	}
+	ret := ComponentScore{Angle: angle, Visible: true}
+	switch {
+	case angle <= 5:
+		ret.Score = 1
+	case angle <= 20:
+		ret.Score = 2
+	case angle <= 60:
+		ret.Score = 3
+	default:
+		ret.Score = 4
+	}
+	return ret
+}
+
+// legs scores legs balanced if both knee angles are within unbalanced angle
+func legs(h openpose.Human, imgW float64, imgH float64, unbalanced float64) ComponentScore {
+	right, foundRight := h.JointAngle(openpose.CocoPartRKnee, imgW, imgH)
+	left, foundLeft := h.JointAngle(openpose.CocoPartLKnee, imgW, imgH)
+	if !foundRight || !foundLeft {
+		return ComponentScore{Score: 1}
+	}
+	ret := ComponentScore{Angle: math.Abs(right - left), Score: 1, Visible: true}
+	if ret.Angle > unbalanced {
+		ret.Score = 2
+	}
+	return ret
+}
+
+// actionLevel returns RULA action level of grand score
+func actionLevel(score int) int {
+	switch {
+	case score <= 2:
+		return 1
+	case score <= 4:
+		return 2
+	case score <= 6:
+		return 3
+	}
+	return 4
+}
@@ -0,0 +1,182 @@
+package ergonomics
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/internal/posetest"
+)
+
+// neutral returns side view of a worker facing right with forearms forward
+func neutral() openpose.Human {
+	return posetest.Human(map[openpose.CocoPart]openpose.Point{
+		openpose.CocoPartNose:      openpose.Pt(335, 75),
+		openpose.CocoPartREar:      openpose.Pt(322, 70),
+		openpose.CocoPartNeck:      openpose.Pt(320, 100),
+		openpose.CocoPartRShoulder: openpose.Pt(320, 100),
+		openpose.CocoPartRElbow:    openpose.Pt(320, 160),
+		openpose.CocoPartRWrist:    openpose.Pt(380, 160),
+		openpose.CocoPartRHip:      openpose.Pt(320, 220),
+		openpose.CocoPartRKnee:     openpose.Pt(320, 320),
+		openpose.CocoPartRAnkle:    openpose.Pt(320, 420),
+		openpose.CocoPartLHip:      openpose.Pt(320, 220),
+		openpose.CocoPartLKnee:     openpose.Pt(320, 320),
+		openpose.CocoPartLAnkle:    openpose.Pt(320, 420),
+	}, 640, 480)
+}
+
+// bent returns side view of a worker facing right bending the trunk 70 degrees and reaching down
+func bent() openpose.Human {
+	neck := openpose.Pt(432.8, 179)
+	return posetest.Human(map[openpose.CocoPart]openpose.Point{
+		openpose.CocoPartNose:      neck.Add(openpose.Pt(50, 15)),
+		openpose.CocoPartREar:      neck.Add(openpose.Pt(35, 5)),
+		openpose.CocoPartNeck:      neck,
+		openpose.CocoPartRShoulder: neck,
+		openpose.CocoPartRElbow:    neck.Add(openpose.Pt(0, 60)),
+		openpose.CocoPartRWrist:    neck.Add(openpose.Pt(0, 120)),
+		openpose.CocoPartRHip:      openpose.Pt(320, 220),
+		openpose.CocoPartRKnee:     openpose.Pt(320, 320),
+		openpose.CocoPartRAnkle:    openpose.Pt(320, 420),
+		openpose.CocoPartLHip:      openpose.Pt(320, 220),
+		openpose.CocoPartLKnee:     openpose.Pt(320, 320),
+		openpose.CocoPartLAnkle:    openpose.Pt(320, 420),
+	}, 640, 480)
+}
+
+// worksheet rows of the published RULA tables, McAtamney and Corlett 1993
+var (
+	// upper arm, lower arm: wrist 1 twist 1, wrist 1 twist 2, ... wrist 4 twist 2
+	worksheetA = `
+1 1: 1 2 2 2 2 3 3 3
+1 2: 2 2 2 2 3 3 3 3
+1 3: 2 3 3 3 3 3 4 4
+2 1: 2 3 3 3 3 4 4 4
+2 2: 3 3 3 3 3 4 4 4
+2 3: 3 4 4 4 4 4 5 5
+3 1: 3 3 4 4 4 4 5 5
+3 2: 3 4 4 4 4 4 5 5
+3 3: 4 4 4 4 4 5 5 5
+4 1: 4 4 4 4 4 5 5 5
+4 2: 4 4 4 4 4 5 5 5
+4 3: 4 4 4 5 5 5 6 6
+5 1: 5 5 5 5 5 6 6 7
+5 2: 5 6 6 6 6 7 7 7
+5 3: 6 6 6 7 7 7 7 8
+6 1: 7 7 7 7 7 8 8 9
+6 2: 8 8 8 8 8 9 9 9
+6 3: 9 9 9 9 9 9 9 9`
+	// neck: trunk 1 legs 1, trunk 1 legs 2, ... trunk 6 legs 2
+	worksheetB = `
+1: 1 3 2 3 3 4 5 5 6 6 7 7
+2: 2 3 2 3 4 5 5 5 6 7 7 7
+3: 3 3 3 4 4 5 5 6 6 7 7 7
+4: 5 5 5 6 6 7 7 7 7 7 8 8
+5: 7 7 7 7 7 8 8 8 8 8 8 8
+6: 8 8 8 8 8 8 8 9 9 9 9 9`
+	// wrist and arm score: neck, trunk and leg score 1 to 7+
+	worksheetC = `
+1: 1 2 3 3 4 5 5
+2: 2 2 3 4 4 5 5
+3: 3 3 3 4 4 5 6
+4: 3 3 3 4 5 6 6
+5: 4 4 4 5 6 7 7
+6: 4 4 5 6 6 7 7
+7: 5 5 6 6 7 7 7
+8: 5 5 6 7 7 7 7`
+)
+
+// parseWorksheet returns row keys and cells of worksheet rows
+func parseWorksheet(t *testing.T, worksheet string) ([][]int, [][]int) {
+	var keys, cells [][]int
+	for _, line := range strings.Split(strings.TrimSpace(worksheet), "\n") {
+		parts := strings.SplitN(line, ":", 2)
+		keys = append(keys, atoi(t, strings.Fields(parts[0])))
+		cells = append(cells, atoi(t, strings.Fields(parts[1])))
+	}
+	return keys, cells
+}
+
+func atoi(t *testing.T, fields []string) []int {
+	ret := make([]int, 0, len(fields))
+	for _, field := range fields {
+		v, err := strconv.Atoi(field)
+		assert.NoError(t, err)
+		ret = append(ret, v)
+	}
+	return ret
+}
+
+func TestTables(t *testing.T) {
+	keys, cells := parseWorksheet(t, worksheetA)
+	assert.Len(t, keys, 18)
+	for row, key := range keys {
+		for col, want := range cells[row] {
+			assert.Equal(t, want, lookupA(key[0], key[1], col/2+1, col%2+1), "table A upper arm %d lower arm %d wrist %d twist %d", key[0], key[1], col/2+1, col%2+1)
+		}
+	}
+	keys, cells = parseWorksheet(t, worksheetB)
+	assert.Len(t, keys, 6)
+	for row, key := range keys {
+		for col, want := range cells[row] {
+			assert.Equal(t, want, lookupB(key[0], col/2+1, col%2+1), "table B neck %d trunk %d legs %d", key[0], col/2+1, col%2+1)
+		}
+	}
+	keys, cells = parseWorksheet(t, worksheetC)
+	assert.Len(t, keys, 8)
+	for row, key := range keys {
+		for col, want := range cells[row] {
+			assert.Equal(t, want, lookupC(key[0], col+1), "table C wrist and arm %d neck, trunk and leg %d", key[0], col+1)
+		}
+	}
+	// scores beyond the tables use the last row or column
+	assert.Equal(t, 7, lookupC(9, 8))
+}
+
+func TestAssess(t *testing.T) {
+	cfg := DefaultConfig()
+	a := Assess(neutral(), 640, 480, cfg)
+	assert.Equal(t, 1, a.Score)
+	assert.Equal(t, 1, a.ActionLevel)
+	assert.Equal(t, []Component{LeftUpperArm, LeftLowerArm}, a.Missing)
+
+	a = Assess(bent(), 640, 480, cfg)
+	assert.Equal(t, 3, a.Components[RightUpperArm].Score)
+	assert.Equal(t, 2, a.Components[RightLowerArm].Score)
+	assert.Equal(t, 3, a.Components[Neck].Score)
+	assert.Equal(t, 4, a.Components[Trunk].Score)
+	assert.Equal(t, 3, a.ScoreA)
+	assert.Equal(t, 5, a.ScoreB)
+	assert.Equal(t, 4, a.Score)
+	assert.Equal(t, 2, a.ActionLevel)
+
+	cfg.ForceScore = 2
+	a = Assess(bent(), 640, 480, cfg)
+	assert.Equal(t, 7, a.Score)
+	assert.Equal(t, 4, a.ActionLevel)
+
+	assert.False(t, Assess(openpose.Human{}, 640, 480, cfg).Valid())
+}
+
+func TestSession(t *testing.T) {
+	cfg := DefaultConfig()
+	s := NewSession(5 * time.Second)
+	start := time.Now()
+	s.Add(Assess(neutral(), 640, 480, cfg), start)
+	s.Add(Assess(bent(), 640, 480, cfg), start.Add(time.Second))
+	s.Add(Assess(neutral(), 640, 480, cfg), start.Add(3*time.Second))
+	s.Add(Assessment{}, start.Add(4*time.Second))
+	summary := s.Summary()
+	assert.Equal(t, 3, summary.Frames)
+	assert.Equal(t, 4*time.Second, summary.Duration)
+	assert.InDelta(t, 2.5, summary.MeanScore, 1e-9)
+	assert.Equal(t, 4, summary.MaxScore)
+	assert.Equal(t, start.Add(time.Second), summary.PeakTime)
+	assert.InDelta(t, 0.5, summary.ActionLevelShare(2), 1e-9)
+	assert.InDelta(t, 2.5, summary.Components[Trunk], 1e-9)
+}
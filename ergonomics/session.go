@@ -0,0 +1,119 @@
+package ergonomics
+
+import (
+	"sync"
+	"time"
+)
+
+// Summary represents aggregated assessments of a work session
+type Summary struct {
+	// Frames assessed frames with visible components
+	Frames int `json:"frames"`
+	// Duration assessed duration
+	Duration time.Duration `json:"duration"`
+	// MeanScore time weighted mean grand score
+	MeanScore float64 `json:"mean_score"`
+	// MaxScore max grand score
+	MaxScore int `json:"max_score"`
+	// Peak assessment of the max grand score
+	Peak Assessment `json:"peak"`
+	// PeakTime time of the peak
+	PeakTime time.Time `json:"peak_time"`
+	// ActionLevels duration spent in each action level
+	ActionLevels map[int]time.Duration `json:"action_levels"`
+	// Components time weighted mean score of each component while visible
+	Components map[Component]float64 `json:"components"`
+}
+
+// ActionLevelShare returns share of duration spent in action level
+func (s Summary) ActionLevelShare(level int) float64 {
+	if s.Duration <= 0 {
+		return 0
+	}
+	return float64(s.ActionLevels[level]) / float64(s.Duration)
+}
+
+// Session aggregates assessments of a subject over a work session
+type Session struct {
+	maxGap        time.Duration
+	summary       Summary
+	scoreSum      float64
+	componentSum  map[Component]float64
+	componentTime map[Component]time.Duration
+	last          Assessment
+	lastTime      time.Time
+	mutex         sync.Mutex
+}
+
+// NewSession returns a new Session, an assessment lasts until the next one and at most maxGap
+func NewSession(maxGap time.Duration) *Session {
+	return &Session{
+		maxGap: maxGap,
+		summary: Summary{
+			ActionLevels: make(map[int]time.Duration, 4),
+			Components:   make(map[Component]float64, len(Components)),
+		},
+		componentSum:  make(map[Component]float64, len(Components)),
+		componentTime: make(map[Component]time.Duration, len(Components)),
+	}
+}
+
+// Add adds assessment at t, invalid assessments only close the previous one
+func (s *Session) Add(a Assessment, t time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.lastTime.IsZero() && s.last.Valid() {
+		dt := t.Sub(s.lastTime)
+		if dt > s.maxGap {
+			dt = s.maxGap
+		}
+		if dt > 0 {
+			s.account(s.last, dt)
+		}
+	}
+	s.last, s.lastTime = a, t
+	if !a.Valid() {
+		return
+	}
+	s.summary.Frames++
+	if a.Score > s.summary.MaxScore {
+		s.summary.MaxScore = a.Score
+		s.summary.Peak = a
+		s.summary.PeakTime = t
+	}
+}
+
+// account attributes duration dt to assessment
+func (s *Session) account(a Assessment, dt time.Duration) {
+	s.summary.Duration += dt
+	s.scoreSum += float64(a.Score) * dt.Seconds()
+	s.summary.ActionLevels[a.ActionLevel] += dt
+	for component, score := range a.Components {
+		if !score.Visible {
+			continue
+		}
+		s.componentSum[component] += float64(score.Score) * dt.Seconds()
+		s.componentTime[component] += dt
+	}
+}
+
+// Summary returns session summary
+func (s *Session) Summary() Summary {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	ret := s.summary
+	ret.ActionLevels = make(map[int]time.Duration, len(s.summary.ActionLevels))
+	for level, d := range s.summary.ActionLevels {
+		ret.ActionLevels[level] = d
+	}
+	if ret.Duration > 0 {
+		ret.MeanScore = s.scoreSum / ret.Duration.Seconds()
+	}
+	ret.Components = make(map[Component]float64, len(s.componentSum))
+	for component, sum := range s.componentSum {
+		if d := s.componentTime[component]; d > 0 {
+			ret.Components[component] = sum / d.Seconds()
+		}
+	}
+	return ret
+}
@@ -0,0 +1,82 @@
+package ergonomics
+
+// tableA RULA table A, upper arm x lower arm x (wrist, wrist twist)
+var tableA = [6][3][8]int{
+	{
+		{1, 2, 2, 2, 2, 3, 3, 3},
+		{2, 2, 2, 2, 3, 3, 3, 3},
+		{2, 3, 3, 3, 3, 3, 4, 4},
+	},
+	{
+		{2, 3, 3, 3, 3, 4, 4, 4},
+		{3, 3, 3, 3, 3, 4, 4, 4},
+		{3, 4, 4, 4, 4, 4, 5, 5},
+	},
+	{
+		{3, 3, 4, 4, 4, 4, 5, 5},
+		{3, 4, 4, 4, 4, 4, 5, 5},
+		{4, 4, 4, 4, 4, 5, 5, 5},
+	},
+	{
+		{4, 4, 4, 4, 4, 5, 5, 5},
+		{4, 4, 4, 4, 4, 5, 5, 5},
+		{4, 4, 4, 5, 5, 5, 6, 6},
+	},
+	{
+		{5, 5, 5, 5, 5, 6, 6, 7},
+		{5, 6, 6, 6, 6, 7, 7, 7},
+		{6, 6, 6, 7, 7, 7, 7, 8},
+	},
+	{
+		{7, 7, 7, 7, 7, 8, 8, 9},
+		{8, 8, 8, 8, 8, 9, 9, 9},
+		{9, 9, 9, 9, 9, 9, 9, 9},
+	},
+}
+
+// tableB RULA table B, neck x (trunk, legs)
+var tableB = [6][12]int{
+	{1, 3, 2, 3, 3, 4, 5, 5, 6, 6, 7, 7},
+	{2, 3, 2, 3, 4, 5, 5, 5, 6, 7, 7, 7},
+	{3, 3, 3, 4, 4, 5, 5, 6, 6, 7, 7, 7},
+	{5, 5, 5, 6, 6, 7, 7, 7, 7, 7, 8, 8},
+	{7, 7, 7, 7, 7, 8, 8, 8, 8, 8, 8, 8},
+	{8, 8, 8, 8, 8, 8, 8, 9, 9, 9, 9, 9},
+}
+
+// tableC RULA table C, wrist and arm score x neck, trunk and leg score
+var tableC = [8][7]int{
+	{1, 2, 3, 3, 4, 5, 5},
+	{2, 2, 3, 4, 4, 5, 5},
+	{3, 3, 3, 4, 4, 5, 6},
+	{3, 3, 3, 4, 5, 6, 6},
+	{4, 4, 4, 5, 6, 7, 7},
+	{4, 4, 5, 6, 6, 7, 7},
+	{5, 5, 6, 6, 7, 7, 7},
+	{5, 5, 6, 7, 7, 7, 7},
+}
+
+// lookupA returns table A score, scores out of range are clamped
+func lookupA(upperArm int, lowerArm int, wrist int, twist int) int {
+	return tableA[clamp(upperArm, 1, 6)-1][clamp(lowerArm, 1, 3)-1][(clamp(wrist, 1, 4)-1)*2+clamp(twist, 1, 2)-1]
+}
+
+// lookupB returns table B score, scores out of range are clamped
+func lookupB(neck int, trunk int, legs int) int {
+	return tableB[clamp(neck, 1, 6)-1][(clamp(trunk, 1, 6)-1)*2+clamp(legs, 1, 2)-1]
+}
+
+// lookupC returns table C grand score, scores out of range are clamped
+func lookupC(a int, b int) int {
+	return tableC[clamp(a, 1, 8)-1][clamp(b, 1, 7)-1]
+}
+
+func clamp(v int, min int, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
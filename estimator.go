@@ -62,11 +62,27 @@ func (t *PoseEstimator) Estimate(img image.Image, modelSize ModelSize) ([]Human,
 	return t.estimatePose(preprocessedImage, normPadding)
 }
 
+// EstimateTransform returns estimated Humans in img transformed by transform, e.g. rotated or mirrored camera frames.
+// Points are mapped back to img, normalized to its size
+func (t *PoseEstimator) EstimateTransform(img image.Image, modelSize ModelSize, transform Transform) ([]Human, error) {
+	humans, err := t.Estimate(transform.Apply(img), modelSize)
+	if err != nil {
+		return nil, err
+	}
+	return transform.Humans(humans, img.Bounds()), nil
+}
+
 func (t *PoseEstimator) estimatePose(img image.Image, normPadding Size) ([]Human, error) {
 	pafMat, heatMat, err := t.getMats(img)
 	if err != nil {
 		return nil, err
 	}
+	return t.assemblePose(pafMat, heatMat, normPadding)
+}
+
+// assemblePose returns humans assembled from part affinity fields and heatmaps, normPadding is the ratio of
+// the image to the padded model input the mats cover
+func (t *PoseEstimator) assemblePose(pafMat [][][]float32, heatMat [][][]float32, normPadding Size) ([]Human, error) {
 	heatMat = gaussian.ApplyFilter(heatMat, 5, 2.5)
 	//dump(pafMat, "./pafMat.json")
 	//dump(heatMat, "./heatMat.json")
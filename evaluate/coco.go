@@ -0,0 +1,187 @@
+package evaluate
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/bububa/openpose"
+)
+
+// CocoKeypointParts maps COCO-17 keypoint order to CocoPart, COCO has no neck
+var CocoKeypointParts = [17]openpose.CocoPart{
+	openpose.CocoPartNose,
+	openpose.CocoPartLEye,
+	openpose.CocoPartREye,
+	openpose.CocoPartLEar,
+	openpose.CocoPartREar,
+	openpose.CocoPartLShoulder,
+	openpose.CocoPartRShoulder,
+	openpose.CocoPartLElbow,
+	openpose.CocoPartRElbow,
+	openpose.CocoPartLWrist,
+	openpose.CocoPartRWrist,
+	openpose.CocoPartLHip,
+	openpose.CocoPartRHip,
+	openpose.CocoPartLKnee,
+	openpose.CocoPartRKnee,
+	openpose.CocoPartLAnkle,
+	openpose.CocoPartRAnkle,
+}
+
+// PersonCategoryID COCO person category id
+const PersonCategoryID = 1
+
+// Dataset represents COCO person_keypoints ground-truth
+type Dataset struct {
+	Images      []Image      `json:"images"`
+	Annotations []Annotation `json:"annotations"`
+}
+
+// Image represents COCO image
+type Image struct {
+	ID       int64  `json:"id"`
+	FileName string `json:"file_name"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+}
+
+// Annotation represents COCO person keypoints annotation
+type Annotation struct {
+	ID           int64      `json:"id"`
+	ImageID      int64      `json:"image_id"`
+	CategoryID   int        `json:"category_id"`
+	Keypoints    []float64  `json:"keypoints"`
+	NumKeypoints int        `json:"num_keypoints"`
+	Area         float64    `json:"area"`
+	BBox         [4]float64 `json:"bbox"`
+	IsCrowd      int        `json:"iscrowd"`
+}
+
+// Detection represents COCO keypoint detection result
+type Detection struct {
+	ImageID    int64     `json:"image_id"`
+	CategoryID int       `json:"category_id"`
+	Keypoints  []float64 `json:"keypoints"`
+	Score      float64   `json:"score"`
+}
+
+// Area returns area of keypoints extent, same as COCO loadRes
+func (d Detection) Area() float64 {
+	return keypointsArea(d.Keypoints)
+}
+
+// LoadDataset decode COCO ground-truth json
+func LoadDataset(r io.Reader) (*Dataset, error) {
+	var ds Dataset
+	if err := json.NewDecoder(r).Decode(&ds); err != nil {
+		return nil, err
+	}
+	return &ds, nil
+}
+
+// LoadDatasetFile load COCO ground-truth json file
+func LoadDatasetFile(filePath string) (*Dataset, error) {
+	fn, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer fn.Close()
+	return LoadDataset(fn)
+}
+
+// LoadDetections decode COCO keypoint results json
+func LoadDetections(r io.Reader) ([]Detection, error) {
+	var dts []Detection
+	if err := json.NewDecoder(r).Decode(&dts); err != nil {
+		return nil, err
+	}
+	return dts, nil
+}
+
+// LoadDetectionsFile load COCO keypoint results json file
+func LoadDetectionsFile(filePath string) ([]Detection, error) {
+	fn, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer fn.Close()
+	return LoadDetections(fn)
+}
+
+// WriteDetections encode detections as COCO keypoint results json
+func WriteDetections(w io.Writer, dts []Detection) error {
+	return json.NewEncoder(w).Encode(dts)
+}
+
+// HumanToKeypoints returns COCO-17 keypoints [x, y, v] in pixels of human compared to img size (w, h)
+func HumanToKeypoints(h openpose.Human, imgW float64, imgH float64) []float64 {
+	ret := make([]float64, 0, len(CocoKeypointParts)*3)
+	for _, part := range CocoKeypointParts {
+		bodyPart, found := h.Parts[part]
+		if !found {
+			ret = append(ret, 0, 0, 0)
+			continue
+		}
+		point := bodyPart.Point.Scale(imgW, imgH)
+		ret = append(ret, point.X, point.Y, 2)
+	}
+	return ret
+}
+
+// KeypointsToHuman returns Human of COCO-17 keypoints in pixels compared to img size (w, h).
+// Keypoints with visibility 0 are not labeled and skipped
+func KeypointsToHuman(keypoints []float64, imgW float64, imgH float64) openpose.Human {
+	h := openpose.NewHuman()
+	for idx, part := range CocoKeypointParts {
+		if len(keypoints) < idx*3+3 || keypoints[idx*3+2] <= 0 {
+			continue
+		}
+		point := openpose.Pt(keypoints[idx*3]/imgW, keypoints[idx*3+1]/imgH)
+		h.Parts[part] = openpose.NewBodyPart(part, point, 1)
+	}
+	h.Score = 1
+	return *h
+}
+
+// NewDetections returns detections of humans estimated in image
+func NewDetections(imageID int64, humans []openpose.Human, imgW float64, imgH float64) []Detection {
+	ret := make([]Detection, 0, len(humans))
+	for _, h := range humans {
+		ret = append(ret, Detection{
+			ImageID:    imageID,
+			CategoryID: PersonCategoryID,
+			Keypoints:  HumanToKeypoints(h, imgW, imgH),
+			Score:      float64(h.Score),
+		})
+	}
+	return ret
+}
+
+func keypointsArea(keypoints []float64) float64 {
+	var (
+		x0, y0, x1, y1 float64
+		found          bool
+	)
+	for idx := 0; idx+2 < len(keypoints); idx += 3 {
+		x, y := keypoints[idx], keypoints[idx+1]
+		if !found {
+			x0, y0, x1, y1 = x, y, x, y
+			found = true
+			continue
+		}
+		if x < x0 {
+			x0 = x
+		}
+		if y < y0 {
+			y0 = y
+		}
+		if x > x1 {
+			x1 = x
+		}
+		if y > y1 {
+			y1 = y
+		}
+	}
+	return (x1 - x0) * (y1 - y0)
+}
@@ -0,0 +1,40 @@
+package evaluate
+
+import (
+	"image"
+	// register image decoders
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+
+	"github.com/bububa/openpose"
+)
+
+// Detect runs estimator over dataset images in imageDir and returns detections in dataset image order
+func Detect(e *openpose.PoseEstimator, ds *Dataset, imageDir string, modelSize openpose.ModelSize) ([]Detection, error) {
+	var dts []Detection
+	for _, img := range ds.Images {
+		src, err := loadImage(filepath.Join(imageDir, img.FileName))
+		if err != nil {
+			return nil, err
+		}
+		humans, err := e.Estimate(src, modelSize)
+		if err != nil {
+			return nil, err
+		}
+		bounds := src.Bounds()
+		dts = append(dts, NewDetections(img.ID, humans, float64(bounds.Dx()), float64(bounds.Dy()))...)
+	}
+	return dts, nil
+}
+
+func loadImage(filePath string) (image.Image, error) {
+	fn, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer fn.Close()
+	img, _, err := image.Decode(fn)
+	return img, err
+}
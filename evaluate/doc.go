@@ -0,0 +1,2 @@
+// Package evaluate COCO keypoint evaluation
+package evaluate
@@ -0,0 +1,354 @@
+package evaluate
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/bububa/openpose/similarity"
+)
+
+// AreaRange represents object area range in pixels
+type AreaRange struct {
+	Name string
+	Min  float64
+	Max  float64
+}
+
+// Params represents evaluation params
+type Params struct {
+	// OKSThresholds OKS thresholds for matching
+	OKSThresholds []float64
+	// RecallThresholds recall thresholds for interpolated precision
+	RecallThresholds []float64
+	// AreaRanges object area ranges, the first one must cover all areas
+	AreaRanges []AreaRange
+	// MaxDets max detections per image
+	MaxDets int
+}
+
+// DefaultParams returns COCO keypoint evaluation params
+func DefaultParams() Params {
+	params := Params{
+		OKSThresholds:    make([]float64, 0, 10),
+		RecallThresholds: make([]float64, 0, 101),
+		AreaRanges: []AreaRange{
+			{Name: "all", Min: 0, Max: 1e10},
+			{Name: "medium", Min: 32 * 32, Max: 96 * 96},
+			{Name: "large", Min: 96 * 96, Max: 1e10},
+		},
+		MaxDets: 20,
+	}
+	for i := 0; i < 10; i++ {
+		params.OKSThresholds = append(params.OKSThresholds, 0.5+0.05*float64(i))
+	}
+	for i := 0; i <= 100; i++ {
+		params.RecallThresholds = append(params.RecallThresholds, 0.01*float64(i))
+	}
+	return params
+}
+
+// Result represents COCO keypoint evaluation summary, -1 means no ground-truth for the metric
+type Result struct {
+	AP       float64 `json:"ap"`
+	AP50     float64 `json:"ap50"`
+	AP75     float64 `json:"ap75"`
+	APMedium float64 `json:"ap_medium"`
+	APLarge  float64 `json:"ap_large"`
+	AR       float64 `json:"ar"`
+	AR50     float64 `json:"ar50"`
+	AR75     float64 `json:"ar75"`
+	ARMedium float64 `json:"ar_medium"`
+	ARLarge  float64 `json:"ar_large"`
+	// Images evaluated images count
+	Images int `json:"images"`
+	// GroundTruths non ignored ground-truth count
+	GroundTruths int `json:"ground_truths"`
+	// Detections evaluated detections count
+	Detections int `json:"detections"`
+}
+
+// WriteTable writes human-readable result table like COCO summarize
+func (r Result) WriteTable(w io.Writer) error {
+	rows := []struct {
+		name  string
+		iou   string
+		area  string
+		value float64
+	}{
+		{"Average Precision  (AP)", "0.50:0.95", "all", r.AP},
+		{"Average Precision  (AP)", "0.50", "all", r.AP50},
+		{"Average Precision  (AP)", "0.75", "all", r.AP75},
+		{"Average Precision  (AP)", "0.50:0.95", "medium", r.APMedium},
+		{"Average Precision  (AP)", "0.50:0.95", "large", r.APLarge},
+		{"Average Recall     (AR)", "0.50:0.95", "all", r.AR},
+		{"Average Recall     (AR)", "0.50", "all", r.AR50},
+		{"Average Recall     (AR)", "0.75", "all", r.AR75},
+		{"Average Recall     (AR)", "0.50:0.95", "medium", r.ARMedium},
+		{"Average Recall     (AR)", "0.50:0.95", "large", r.ARLarge},
+	}
+	for _, row := range rows {
+		if _, err := fmt.Fprintf(w, " %s @[ OKS=%9s | area=%6s ] = %.3f\n", row.name, row.iou, row.area, row.value); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, " images=%d ground_truths=%d detections=%d\n", r.Images, r.GroundTruths, r.Detections)
+	return err
+}
+
+// evalImage represents matching result of an image for an area range
+type evalImage struct {
+	scores    []float64
+	dtMatched [][]bool
+	dtIgnore  [][]bool
+	gtCount   int
+}
+
+// Evaluate returns COCO keypoint AP/AR of detections against ground-truth.
+// The result only depends on inputs, detections with the same score keep their input order
+func Evaluate(ds *Dataset, dts []Detection, params Params) Result {
+	images := make(map[int64]Image, len(ds.Images))
+	for _, img := range ds.Images {
+		images[img.ID] = img
+	}
+	gtsByImage := make(map[int64][]Annotation, len(ds.Images))
+	for _, gt := range ds.Annotations {
+		if gt.CategoryID != PersonCategoryID {
+			continue
+		}
+		gtsByImage[gt.ImageID] = append(gtsByImage[gt.ImageID], gt)
+	}
+	dtsByImage := make(map[int64][]Detection, len(ds.Images))
+	for _, dt := range dts {
+		if _, found := images[dt.ImageID]; !found || dt.CategoryID != PersonCategoryID {
+			continue
+		}
+		dtsByImage[dt.ImageID] = append(dtsByImage[dt.ImageID], dt)
+	}
+	result := Result{Images: len(ds.Images)}
+	precisions := make([][]float64, len(params.AreaRanges))
+	recalls := make([][]float64, len(params.AreaRanges))
+	for areaIdx, areaRange := range params.AreaRanges {
+		evals := make([]evalImage, 0, len(ds.Images))
+		for _, img := range ds.Images {
+			evals = append(evals, evaluateImage(img, gtsByImage[img.ID], dtsByImage[img.ID], areaRange, params))
+		}
+		precisions[areaIdx], recalls[areaIdx] = accumulate(evals, params)
+		if areaIdx == 0 {
+			for _, e := range evals {
+				result.GroundTruths += e.gtCount
+				result.Detections += len(e.scores)
+			}
+		}
+	}
+	thresholdIdx := func(threshold float64) int {
+		for idx, t := range params.OKSThresholds {
+			if math.Abs(t-threshold) < 1e-9 {
+				return idx
+			}
+		}
+		return -1
+	}
+	idx50, idx75 := thresholdIdx(0.5), thresholdIdx(0.75)
+	recallSteps := len(params.RecallThresholds)
+	result.AP = mean(precisions[0], recallSteps, -1)
+	result.AP50 = mean(precisions[0], recallSteps, idx50)
+	result.AP75 = mean(precisions[0], recallSteps, idx75)
+	result.AR = mean(recalls[0], 1, -1)
+	result.AR50 = mean(recalls[0], 1, idx50)
+	result.AR75 = mean(recalls[0], 1, idx75)
+	result.APMedium, result.APLarge, result.ARMedium, result.ARLarge = -1, -1, -1, -1
+	for areaIdx, areaRange := range params.AreaRanges {
+		switch areaRange.Name {
+		case "medium":
+			result.APMedium = mean(precisions[areaIdx], recallSteps, -1)
+			result.ARMedium = mean(recalls[areaIdx], 1, -1)
+		case "large":
+			result.APLarge = mean(precisions[areaIdx], recallSteps, -1)
+			result.ARLarge = mean(recalls[areaIdx], 1, -1)
+		}
+	}
+	return result
+}
+
+// evaluateImage matches detections to ground-truth of an image for every OKS threshold
+func evaluateImage(img Image, gts []Annotation, dts []Detection, areaRange AreaRange, params Params) evalImage {
+	imgW, imgH := float64(img.Width), float64(img.Height)
+	gtIgnore := make([]bool, len(gts))
+	for idx, gt := range gts {
+		gtIgnore[idx] = gt.IsCrowd != 0 || gt.NumKeypoints == 0 || gt.Area < areaRange.Min || gt.Area > areaRange.Max
+	}
+	// non ignored ground-truth first
+	gtOrder := make([]int, len(gts))
+	for idx := range gtOrder {
+		gtOrder[idx] = idx
+	}
+	sort.SliceStable(gtOrder, func(i, j int) bool { return !gtIgnore[gtOrder[i]] && gtIgnore[gtOrder[j]] })
+	dtOrder := make([]int, len(dts))
+	for idx := range dtOrder {
+		dtOrder[idx] = idx
+	}
+	sort.SliceStable(dtOrder, func(i, j int) bool { return dts[dtOrder[i]].Score > dts[dtOrder[j]].Score })
+	if len(dtOrder) > params.MaxDets {
+		dtOrder = dtOrder[:params.MaxDets]
+	}
+	gtHumans := make([]struct {
+		ann    Annotation
+		ignore bool
+	}, len(gtOrder))
+	for i, idx := range gtOrder {
+		gtHumans[i].ann = gts[idx]
+		gtHumans[i].ignore = gtIgnore[idx]
+	}
+	oks := make([][]float64, len(dtOrder))
+	for i, dtIdx := range dtOrder {
+		dtHuman := KeypointsToHuman(dts[dtIdx].Keypoints, imgW, imgH)
+		oks[i] = make([]float64, len(gtHumans))
+		for j, gt := range gtHumans {
+			gtHuman := KeypointsToHuman(gt.ann.Keypoints, imgW, imgH)
+			oks[i][j] = similarity.OKS(gtHuman, dtHuman, imgW, imgH, gt.ann.Area)
+		}
+	}
+	ret := evalImage{
+		scores:    make([]float64, len(dtOrder)),
+		dtMatched: make([][]bool, len(params.OKSThresholds)),
+		dtIgnore:  make([][]bool, len(params.OKSThresholds)),
+	}
+	for i, dtIdx := range dtOrder {
+		ret.scores[i] = dts[dtIdx].Score
+	}
+	for _, gt := range gtHumans {
+		if !gt.ignore {
+			ret.gtCount++
+		}
+	}
+	for tIdx, threshold := range params.OKSThresholds {
+		gtMatched := make([]bool, len(gtHumans))
+		ret.dtMatched[tIdx] = make([]bool, len(dtOrder))
+		ret.dtIgnore[tIdx] = make([]bool, len(dtOrder))
+		for i, dtIdx := range dtOrder {
+			best := math.Min(threshold, 1-1e-10)
+			match := -1
+			for j, gt := range gtHumans {
+				// crowd ground-truth could be matched multiple times
+				if gtMatched[j] && gt.ann.IsCrowd == 0 {
+					continue
+				}
+				// ground-truth is sorted by ignore, stop at the first ignored once matched a real one
+				if match > -1 && !gtHumans[match].ignore && gt.ignore {
+					break
+				}
+				if oks[i][j] < best {
+					continue
+				}
+				best = oks[i][j]
+				match = j
+			}
+			if match > -1 {
+				gtMatched[match] = true
+				ret.dtMatched[tIdx][i] = true
+				ret.dtIgnore[tIdx][i] = gtHumans[match].ignore
+				continue
+			}
+			area := dts[dtIdx].Area()
+			ret.dtIgnore[tIdx][i] = area < areaRange.Min || area > areaRange.Max
+		}
+	}
+	return ret
+}
+
+// accumulate returns interpolated precision per OKS threshold and recall thresholds, and recall per OKS threshold.
+// Thresholds without ground-truth have nil precision and -1 recall
+func accumulate(evals []evalImage, params Params) ([]float64, []float64) {
+	precisions := make([]float64, 0, len(params.OKSThresholds)*len(params.RecallThresholds))
+	recalls := make([]float64, 0, len(params.OKSThresholds))
+	var gtCount int
+	for _, e := range evals {
+		gtCount += e.gtCount
+	}
+	type scoredDt struct {
+		score   float64
+		matched bool
+		ignore  bool
+	}
+	for tIdx := range params.OKSThresholds {
+		if gtCount == 0 {
+			recalls = append(recalls, -1)
+			for range params.RecallThresholds {
+				precisions = append(precisions, -1)
+			}
+			continue
+		}
+		var all []scoredDt
+		for _, e := range evals {
+			for i, score := range e.scores {
+				all = append(all, scoredDt{score, e.dtMatched[tIdx][i], e.dtIgnore[tIdx][i]})
+			}
+		}
+		sort.SliceStable(all, func(i, j int) bool { return all[i].score > all[j].score })
+		var (
+			tp, fp float64
+			rc     = make([]float64, 0, len(all))
+			pr     = make([]float64, 0, len(all))
+		)
+		for _, dt := range all {
+			if dt.ignore {
+				continue
+			}
+			if dt.matched {
+				tp++
+			} else {
+				fp++
+			}
+			rc = append(rc, tp/float64(gtCount))
+			pr = append(pr, tp/(tp+fp+math.SmallestNonzeroFloat64))
+		}
+		if len(rc) > 0 {
+			recalls = append(recalls, rc[len(rc)-1])
+		} else {
+			recalls = append(recalls, 0)
+		}
+		// make precision monotonically decreasing
+		for i := len(pr) - 1; i > 0; i-- {
+			if pr[i] > pr[i-1] {
+				pr[i-1] = pr[i]
+			}
+		}
+		for _, r := range params.RecallThresholds {
+			idx := sort.SearchFloat64s(rc, r)
+			if idx < len(pr) {
+				precisions = append(precisions, pr[idx])
+			} else {
+				precisions = append(precisions, 0)
+			}
+		}
+	}
+	return precisions, recalls
+}
+
+// mean returns the mean of valid values, values are grouped by OKS threshold with step values each,
+// idx selects the OKS threshold or all thresholds if idx < 0
+func mean(values []float64, step int, idx int) float64 {
+	if idx >= 0 {
+		if (idx+1)*step > len(values) {
+			return -1
+		}
+		values = values[idx*step : (idx+1)*step]
+	}
+	var (
+		sum   float64
+		count float64
+	)
+	for _, v := range values {
+		if v < 0 {
+			continue
+		}
+		sum += v
+		count++
+	}
+	if count == 0 {
+		return -1
+	}
+	return sum / count
+}
@@ -0,0 +1,75 @@
+package evaluate
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testDataset() *Dataset {
+	return &Dataset{
+		Images: []Image{
+			{ID: 1, FileName: "1.jpg", Width: 640, Height: 480},
+			{ID: 2, FileName: "2.jpg", Width: 640, Height: 480},
+		},
+		Annotations: []Annotation{
+			{ID: 1, ImageID: 1, CategoryID: PersonCategoryID, Keypoints: testKeypoints(100, 50), NumKeypoints: 17, Area: 150 * 300},
+			{ID: 2, ImageID: 2, CategoryID: PersonCategoryID, Keypoints: testKeypoints(300, 100), NumKeypoints: 17, Area: 150 * 300},
+		},
+	}
+}
+
+func testKeypoints(x float64, y float64) []float64 {
+	ret := make([]float64, 0, 17*3)
+	for idx := 0; idx < 17; idx++ {
+		ret = append(ret, x+float64(idx%3)*50, y+float64(idx)*18, 2)
+	}
+	return ret
+}
+
+func TestEvaluate_PerfectDetections(t *testing.T) {
+	ds := testDataset()
+	dts := []Detection{
+		{ImageID: 1, CategoryID: PersonCategoryID, Keypoints: testKeypoints(100, 50), Score: 0.9},
+		{ImageID: 2, CategoryID: PersonCategoryID, Keypoints: testKeypoints(300, 100), Score: 0.8},
+	}
+	result := Evaluate(ds, dts, DefaultParams())
+	assert.InDelta(t, 1, result.AP, 1e-9)
+	assert.InDelta(t, 1, result.AR, 1e-9)
+	assert.InDelta(t, 1, result.APLarge, 1e-9)
+	assert.Equal(t, -1.0, result.APMedium)
+	assert.Equal(t, 2, result.GroundTruths)
+}
+
+func TestEvaluate_FalsePositiveLowersPrecision(t *testing.T) {
+	ds := testDataset()
+	dts := []Detection{
+		{ImageID: 1, CategoryID: PersonCategoryID, Keypoints: testKeypoints(400, 50), Score: 0.95},
+		{ImageID: 1, CategoryID: PersonCategoryID, Keypoints: testKeypoints(100, 50), Score: 0.9},
+	}
+	result := Evaluate(ds, dts, DefaultParams())
+	assert.InDelta(t, 0.5, result.AR, 1e-9)
+	assert.Less(t, result.AP, 0.5)
+	again := Evaluate(ds, dts, DefaultParams())
+	assert.Equal(t, result, again)
+}
+
+func TestDetections_RoundTrip(t *testing.T) {
+	dts := []Detection{
+		{ImageID: 1, CategoryID: PersonCategoryID, Keypoints: testKeypoints(100, 50), Score: 0.9},
+	}
+	var buf bytes.Buffer
+	assert.Nil(t, WriteDetections(&buf, dts))
+	loaded, err := LoadDetections(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, dts, loaded)
+}
+
+func TestKeypointsToHuman_MapsCocoOrder(t *testing.T) {
+	kps := testKeypoints(100, 50)
+	h := KeypointsToHuman(kps, 640, 480)
+	assert.Equal(t, 17, h.PartCount())
+	back := HumanToKeypoints(h, 640, 480)
+	assert.InDeltaSlice(t, kps, back, 1e-9)
+}
@@ -0,0 +1,240 @@
+package exercise
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bububa/openpose"
+)
+
+// Phase represents rep phase
+type Phase int
+
+const (
+	// PhaseRest at rest position
+	PhaseRest Phase = iota
+	// PhaseMoving moving from rest towards target
+	PhaseMoving
+	// PhaseTarget at target position
+	PhaseTarget
+	// PhaseReturning returning from target to rest
+	PhaseReturning
+)
+
+// String returns Phase name
+func (p Phase) String() string {
+	switch p {
+	case PhaseMoving:
+		return "moving"
+	case PhaseTarget:
+		return "target"
+	case PhaseReturning:
+		return "returning"
+	}
+	return "rest"
+}
+
+// InsufficientDepth warning of a rep not reaching target
+const InsufficientDepth = "insufficient depth"
+
+// TooFast warning of a rep shorter than definition min rep duration
+const TooFast = "too fast"
+
+// Rep represents a finished repetition
+type Rep struct {
+	// Index count of complete reps including this one, 0 for partial reps
+	Index int
+	// Complete reports target is reached
+	Complete bool
+	// Start time leaving rest
+	Start time.Time
+	// End time back to rest
+	End time.Time
+	// Outward duration from rest to target
+	Outward time.Duration
+	// Hold duration at target
+	Hold time.Duration
+	// Return duration from target to rest
+	Return time.Duration
+	// Extreme signal value closest to target
+	Extreme float64
+	// Warnings form warnings
+	Warnings []string
+}
+
+// Duration returns rep duration
+func (r Rep) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+// String returns Rep string representation
+func (r Rep) String() string {
+	return fmt.Sprintf("Rep:%d complete:%t duration:%s extreme:%.0f warnings:%v", r.Index, r.Complete, r.Duration(), r.Extreme, r.Warnings)
+}
+
+// Status represents counter status
+type Status struct {
+	// Exercise exercise name
+	Exercise string
+	// Reps complete reps
+	Reps int
+	// Partials partial reps
+	Partials int
+	// Phase current phase
+	Phase Phase
+	// Value latest signal value
+	Value float64
+	// Tempo mean duration of complete reps
+	Tempo time.Duration
+	// Last last finished rep, nil before the first one
+	Last *Rep
+}
+
+// Counter counts reps of an exercise with a hysteresis state machine
+type Counter struct {
+	def      Definition
+	status   Status
+	total    time.Duration
+	rep      Rep
+	deepest  float64
+	targetAt time.Time
+	returnAt time.Time
+	warnings map[string]struct{}
+	mutex    sync.Mutex
+}
+
+// NewCounter returns a new Counter of exercise definition
+func NewCounter(def Definition) (*Counter, error) {
+	if err := def.compile(); err != nil {
+		return nil, err
+	}
+	return &Counter{
+		def:    def,
+		status: Status{Exercise: def.Name},
+	}, nil
+}
+
+// Definition returns exercise definition
+func (c *Counter) Definition() Definition {
+	return c.def
+}
+
+// Status returns counter status
+func (c *Counter) Status() Status {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.status
+}
+
+// Reset resets count and state
+func (c *Counter) Reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.status = Status{Exercise: c.def.Name}
+	c.total = 0
+}
+
+// Update feeds human in pixel space compared to img size (w, h) at t, returns status and the rep finished by this frame if any.
+// Frames without a visible signal are skipped
+func (c *Counter) Update(h openpose.Human, imgW float64, imgH float64, t time.Time) (Status, *Rep) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	value, ok := c.def.Signal.Value(h, imgW, imgH)
+	if !ok {
+		return c.status, nil
+	}
+	c.status.Value = value
+	depth, target := c.def.depth(value), c.def.depth(c.def.Target)
+	if c.status.Phase != PhaseRest {
+		if depth > c.deepest {
+			c.deepest = depth
+			c.rep.Extreme = value
+		}
+		for _, check := range c.def.Checks {
+			if check.failed(h, imgW, imgH) {
+				c.warnings[check.Message] = struct{}{}
+			}
+		}
+	}
+	switch c.status.Phase {
+	case PhaseRest:
+		if depth > 0 {
+			c.start(h, imgW, imgH, value, t)
+		}
+	case PhaseMoving:
+		if depth >= target {
+			c.status.Phase = PhaseTarget
+			c.targetAt = t
+		} else if depth <= 0 {
+			return c.finish(false, t)
+		}
+	case PhaseTarget:
+		if depth < target-c.def.Hysteresis {
+			c.status.Phase = PhaseReturning
+			c.returnAt = t
+		}
+	case PhaseReturning:
+		if depth >= target {
+			c.status.Phase = PhaseTarget
+		} else if depth <= 0 {
+			return c.finish(true, t)
+		}
+	}
+	return c.status, nil
+}
+
+// start starts a rep leaving rest
+func (c *Counter) start(h openpose.Human, imgW float64, imgH float64, value float64, t time.Time) {
+	c.status.Phase = PhaseMoving
+	c.rep = Rep{Start: t, Extreme: value}
+	c.deepest = c.def.depth(value)
+	c.warnings = make(map[string]struct{})
+	for _, check := range c.def.Checks {
+		if check.failed(h, imgW, imgH) {
+			c.warnings[check.Message] = struct{}{}
+		}
+	}
+}
+
+// finish finishes the rep back to rest, movements not passing Partial are dropped
+func (c *Counter) finish(complete bool, t time.Time) (Status, *Rep) {
+	c.status.Phase = PhaseRest
+	if !complete && c.deepest < c.def.depth(c.def.Partial) {
+		return c.status, nil
+	}
+	rep := c.rep
+	rep.End = t
+	rep.Complete = complete
+	if complete {
+		rep.Outward = c.targetAt.Sub(rep.Start)
+		rep.Hold = c.returnAt.Sub(c.targetAt)
+		rep.Return = t.Sub(c.returnAt)
+	} else {
+		c.warnings[InsufficientDepth] = struct{}{}
+	}
+	if c.def.MinRepDuration > 0 && rep.Duration().Seconds() < c.def.MinRepDuration {
+		c.warnings[TooFast] = struct{}{}
+	}
+	// keep warnings in check order
+	for _, check := range c.def.Checks {
+		if _, found := c.warnings[check.Message]; found {
+			rep.Warnings = append(rep.Warnings, check.Message)
+		}
+	}
+	for _, warning := range []string{InsufficientDepth, TooFast} {
+		if _, found := c.warnings[warning]; found {
+			rep.Warnings = append(rep.Warnings, warning)
+		}
+	}
+	if complete {
+		c.status.Reps++
+		c.total += rep.Duration()
+		c.status.Tempo = c.total / time.Duration(c.status.Reps)
+		rep.Index = c.status.Reps
+	} else {
+		c.status.Partials++
+	}
+	c.status.Last = &rep
+	return c.status, &rep
+}
@@ -0,0 +1,104 @@
+package exercise
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/internal/posetest"
+)
+
+// newHuman returns an upright human in a 640x480 image with both knees bent to angle in degrees
+func newHuman(angle float64) openpose.Human {
+	rad := (180 - angle) * math.Pi / 180
+	shin := openpose.Pt(math.Sin(rad), math.Cos(rad)).Mul(100)
+	points := map[openpose.CocoPart]openpose.Point{
+		openpose.CocoPartNeck:  openpose.Pt(320, 100),
+		openpose.CocoPartRHip:  openpose.Pt(300, 250),
+		openpose.CocoPartLHip:  openpose.Pt(340, 250),
+		openpose.CocoPartRKnee: openpose.Pt(300, 350),
+		openpose.CocoPartLKnee: openpose.Pt(340, 350),
+	}
+	points[openpose.CocoPartRAnkle] = points[openpose.CocoPartRKnee].Add(shin)
+	points[openpose.CocoPartLAnkle] = points[openpose.CocoPartLKnee].Add(shin)
+	return posetest.Human(points, 640, 480)
+}
+
+// play feeds reps moving knee angle from rest to target and back in duration, returns finished reps
+func play(c *Counter, start time.Time, rest float64, target float64, duration time.Duration, count int) []Rep {
+	const frames = 30
+	var reps []Rep
+	t := start
+	for i := 0; i < count; i++ {
+		for frame := 0; frame <= frames; frame++ {
+			u := (1 - math.Cos(2*math.Pi*float64(frame)/frames)) / 2
+			if _, rep := c.Update(newHuman(rest+(target-rest)*u), 640, 480, t); rep != nil {
+				reps = append(reps, *rep)
+			}
+			t = t.Add(duration / frames)
+		}
+	}
+	return reps
+}
+
+func TestCounter_Squat(t *testing.T) {
+	def, _ := Builtin("squat")
+	c, err := NewCounter(def)
+	if !assert.NoError(t, err) {
+		return
+	}
+	start := time.Now()
+	reps := play(c, start, 170, 90, 2*time.Second, 3)
+	status := c.Status()
+	assert.Equal(t, 3, status.Reps)
+	assert.Equal(t, PhaseRest, status.Phase)
+	// time above rest angle is not part of a rep
+	assert.InDelta(t, 1.5, status.Tempo.Seconds(), 0.1)
+	if assert.Len(t, reps, 3) {
+		assert.Equal(t, 3, reps[2].Index)
+		assert.Empty(t, reps[2].Warnings)
+		assert.InDelta(t, 90, reps[2].Extreme, 1e-6)
+	}
+	// shallow squat
+	reps = play(c, start.Add(10*time.Second), 170, 120, 2*time.Second, 1)
+	if assert.Len(t, reps, 1) {
+		assert.False(t, reps[0].Complete)
+		assert.Equal(t, []string{InsufficientDepth}, reps[0].Warnings)
+	}
+	// fast squat
+	reps = play(c, start.Add(20*time.Second), 170, 90, 500*time.Millisecond, 1)
+	if assert.Len(t, reps, 1) {
+		assert.True(t, reps[0].Complete)
+		assert.Equal(t, []string{TooFast}, reps[0].Warnings)
+	}
+	assert.Equal(t, 4, c.Status().Reps)
+	assert.Equal(t, 1, c.Status().Partials)
+}
+
+func TestLoadDefinitions(t *testing.T) {
+	defs, err := LoadDefinitions(strings.NewReader(`[{
+		"name": "knee_extension",
+		"signal": {"joints": ["RKnee"]},
+		"rest": 100,
+		"target": 160,
+		"partial": 130,
+		"hysteresis": 10
+	}]`))
+	if !assert.NoError(t, err) || !assert.Len(t, defs, 1) {
+		return
+	}
+	c, err := NewCounter(defs[0])
+	if !assert.NoError(t, err) {
+		return
+	}
+	reps := play(c, time.Now(), 90, 170, 2*time.Second, 2)
+	assert.Len(t, reps, 2)
+	assert.Equal(t, 2, c.Status().Reps)
+
+	_, err = LoadDefinitions(strings.NewReader(`[{"name": "bad", "signal": {"joints": ["Nose"]}, "rest": 1, "target": 2}]`))
+	assert.Error(t, err)
+}
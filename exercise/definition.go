@@ -0,0 +1,270 @@
+package exercise
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/bububa/openpose"
+)
+
+const (
+	// MetricAngle joint angle in degrees, see openpose.JointAngleParts
+	MetricAngle = "angle"
+	// MetricTrunk trunk inclination to vertical in degrees
+	MetricTrunk = "trunk"
+)
+
+const (
+	// AggregateMean mean value of visible joints
+	AggregateMean = "mean"
+	// AggregateMin min value of visible joints
+	AggregateMin = "min"
+	// AggregateMax max value of visible joints
+	AggregateMax = "max"
+)
+
+// Signal represents a measure of human
+type Signal struct {
+	// Metric angle or trunk, angle by default
+	Metric string `json:"metric,omitempty"`
+	// Joints CocoPart names of angle joints
+	Joints []string `json:"joints,omitempty"`
+	// Aggregate mean, min or max of joints, mean by default
+	Aggregate string `json:"aggregate,omitempty"`
+	parts     []openpose.CocoPart
+}
+
+// compile validates signal and parses joint names
+func (s *Signal) compile() error {
+	switch s.Metric {
+	case "", MetricAngle:
+		if len(s.Joints) == 0 {
+			return errors.New("angle signal without joints")
+		}
+	case MetricTrunk:
+	default:
+		return fmt.Errorf("unknown metric: %s", s.Metric)
+	}
+	switch s.Aggregate {
+	case "", AggregateMean, AggregateMin, AggregateMax:
+	default:
+		return fmt.Errorf("unknown aggregate: %s", s.Aggregate)
+	}
+	s.parts = make([]openpose.CocoPart, 0, len(s.Joints))
+	for _, name := range s.Joints {
+		part, err := openpose.ParseCocoPart(name)
+		if err != nil {
+			return err
+		}
+		if _, found := openpose.JointAngleParts[part]; !found {
+			return fmt.Errorf("no angle at joint: %s", name)
+		}
+		s.parts = append(s.parts, part)
+	}
+	return nil
+}
+
+// Value returns signal value of human in pixel space compared to img size (w, h), false if no joint is visible
+func (s Signal) Value(h openpose.Human, imgW float64, imgH float64) (float64, bool) {
+	if s.Metric == MetricTrunk {
+		return h.TrunkInclination(imgW, imgH)
+	}
+	var (
+		ret   float64
+		count int
+	)
+	for _, part := range s.parts {
+		angle, found := h.JointAngle(part, imgW, imgH)
+		if !found {
+			continue
+		}
+		switch {
+		case count == 0:
+			ret = angle
+		case s.Aggregate == AggregateMin:
+			ret = math.Min(ret, angle)
+		case s.Aggregate == AggregateMax:
+			ret = math.Max(ret, angle)
+		default:
+			ret += angle
+		}
+		count++
+	}
+	if count == 0 {
+		return 0, false
+	}
+	if s.Aggregate == "" || s.Aggregate == AggregateMean {
+		ret /= float64(count)
+	}
+	return ret, true
+}
+
+// Check represents a form check evaluated during a rep, zero Min or Max is not checked
+type Check struct {
+	// Message warning message when the check fails
+	Message string `json:"message"`
+	// Signal measure checked
+	Signal Signal `json:"signal"`
+	// Min min allowed value
+	Min float64 `json:"min,omitempty"`
+	// Max max allowed value
+	Max float64 `json:"max,omitempty"`
+}
+
+// failed returns the check fails on human
+func (c Check) failed(h openpose.Human, imgW float64, imgH float64) bool {
+	v, ok := c.Signal.Value(h, imgW, imgH)
+	if !ok {
+		return false
+	}
+	return (c.Min != 0 && v < c.Min) || (c.Max != 0 && v > c.Max)
+}
+
+// Definition represents an exercise. A rep moves the signal from Rest to Target and back,
+// Target could be either below or above Rest
+type Definition struct {
+	// Name exercise name
+	Name string `json:"name"`
+	// Signal measure driving the rep state machine
+	Signal Signal `json:"signal"`
+	// Rest signal value at the rest position
+	Rest float64 `json:"rest"`
+	// Target signal value a full rep must reach
+	Target float64 `json:"target"`
+	// Partial signal value a rep not reaching Target must pass to be reported as partial
+	Partial float64 `json:"partial"`
+	// Hysteresis margin the signal must move back from Target to start returning
+	Hysteresis float64 `json:"hysteresis"`
+	// MinRepDuration min seconds of a rep, faster reps are warned
+	MinRepDuration float64 `json:"min_rep_duration,omitempty"`
+	// Checks form checks
+	Checks []Check `json:"checks,omitempty"`
+}
+
+// compile validates definition and compiles its signals
+func (d *Definition) compile() error {
+	if d.Name == "" {
+		return errors.New("exercise without name")
+	}
+	if d.Rest == d.Target {
+		return fmt.Errorf("exercise %s: rest equals target", d.Name)
+	}
+	if err := d.Signal.compile(); err != nil {
+		return fmt.Errorf("exercise %s: %w", d.Name, err)
+	}
+	checks := make([]Check, len(d.Checks))
+	for idx, check := range d.Checks {
+		if err := check.Signal.compile(); err != nil {
+			return fmt.Errorf("exercise %s: check %s: %w", d.Name, check.Message, err)
+		}
+		checks[idx] = check
+	}
+	d.Checks = checks
+	return nil
+}
+
+// depth returns how far value moved from Rest towards Target, in signal units
+func (d Definition) depth(value float64) float64 {
+	if d.Target < d.Rest {
+		return d.Rest - value
+	}
+	return value - d.Rest
+}
+
+// LoadDefinitions decodes json array of definitions
+func LoadDefinitions(r io.Reader) ([]Definition, error) {
+	var defs []Definition
+	if err := json.NewDecoder(r).Decode(&defs); err != nil {
+		return nil, err
+	}
+	for idx := range defs {
+		if err := defs[idx].compile(); err != nil {
+			return nil, err
+		}
+	}
+	return defs, nil
+}
+
+// LoadDefinitionsFile loads json definitions file
+func LoadDefinitionsFile(filePath string) ([]Definition, error) {
+	fn, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer fn.Close()
+	return LoadDefinitions(fn)
+}
+
+// BuiltinDefinitions represents builtin exercises
+var BuiltinDefinitions = []Definition{
+	{
+		Name:           "squat",
+		Signal:         Signal{Joints: []string{"RKnee", "LKnee"}},
+		Rest:           160,
+		Target:         100,
+		Partial:        135,
+		Hysteresis:     10,
+		MinRepDuration: 1,
+		Checks: []Check{
+			{Message: "leaning forward too much", Signal: Signal{Metric: MetricTrunk}, Max: 50},
+		},
+	},
+	{
+		Name:           "push_up",
+		Signal:         Signal{Joints: []string{"RElbow", "LElbow"}},
+		Rest:           150,
+		Target:         90,
+		Partial:        120,
+		Hysteresis:     10,
+		MinRepDuration: 0.8,
+		Checks: []Check{
+			{Message: "keep hips in line", Signal: Signal{Joints: []string{"RHip", "LHip"}}, Min: 150},
+		},
+	},
+	{
+		Name:           "lunge",
+		Signal:         Signal{Joints: []string{"RKnee", "LKnee"}, Aggregate: AggregateMin},
+		Rest:           160,
+		Target:         100,
+		Partial:        130,
+		Hysteresis:     10,
+		MinRepDuration: 1,
+		Checks: []Check{
+			{Message: "keep torso upright", Signal: Signal{Metric: MetricTrunk}, Max: 30},
+		},
+	},
+	{
+		Name:       "jumping_jack",
+		Signal:     Signal{Joints: []string{"RShoulder", "LShoulder"}},
+		Rest:       40,
+		Target:     140,
+		Partial:    100,
+		Hysteresis: 15,
+	},
+	{
+		Name:           "bicep_curl",
+		Signal:         Signal{Joints: []string{"RElbow", "LElbow"}, Aggregate: AggregateMin},
+		Rest:           150,
+		Target:         50,
+		Partial:        90,
+		Hysteresis:     10,
+		MinRepDuration: 1,
+		Checks: []Check{
+			{Message: "keep upper arm still", Signal: Signal{Joints: []string{"RShoulder", "LShoulder"}, Aggregate: AggregateMax}, Max: 40},
+		},
+	},
+}
+
+// Builtin returns builtin definition by name
+func Builtin(name string) (Definition, bool) {
+	for _, def := range BuiltinDefinitions {
+		if def.Name == name {
+			return def, true
+		}
+	}
+	return Definition{}, false
+}
@@ -0,0 +1,2 @@
+// Package exercise counts exercise repetitions from joint angle time series
+package exercise
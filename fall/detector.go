@@ -0,0 +1,262 @@
+package fall
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bububa/openpose"
+)
+
+// Config represents fall detector config
+type Config struct {
+	// MinDownwardVelocity min downward velocity of both neck and hips in torso lengths per second
+	MinDownwardVelocity float64
+	// VelocitySpan min time span velocities are measured over
+	VelocitySpan time.Duration
+	// UprightInclination max trunk inclination in degrees of an upright body
+	UprightInclination float64
+	// LyingInclination min trunk inclination in degrees of a lying body
+	LyingInclination float64
+	// TransitionWindow max duration from upright to lying of a fall
+	TransitionWindow time.Duration
+	// InactivityDuration duration the body must stay still on the ground before a fall is emitted
+	InactivityDuration time.Duration
+	// InactiveDistance max movement in torso lengths of a still body
+	InactiveDistance float64
+}
+
+// DefaultConfig returns default fall detector config
+func DefaultConfig() Config {
+	return Config{
+		MinDownwardVelocity: 1,
+		VelocitySpan:        200 * time.Millisecond,
+		UprightInclination:  30,
+		LyingInclination:    60,
+		TransitionWindow:    2 * time.Second,
+		InactivityDuration:  3 * time.Second,
+		InactiveDistance:    0.5,
+	}
+}
+
+// Evidence represents signals a fall is detected with
+type Evidence struct {
+	// NeckVelocity peak downward velocity of neck in torso lengths per second
+	NeckVelocity float64
+	// HipVelocity peak downward velocity of mid hip in torso lengths per second
+	HipVelocity float64
+	// UprightInclination trunk inclination in degrees before the fall
+	UprightInclination float64
+	// LyingInclination trunk inclination in degrees after the fall
+	LyingInclination float64
+	// Impact time the body turned horizontal
+	Impact time.Time
+	// Inactivity duration the body stayed still on the ground
+	Inactivity time.Duration
+}
+
+// Event represents a detected fall
+type Event struct {
+	// TrackID track id of the fallen human
+	TrackID int
+	// Time when the event is emitted
+	Time time.Time
+	// Evidence signals of the fall
+	Evidence Evidence
+}
+
+// String returns Event string representation
+func (e Event) String() string {
+	return fmt.Sprintf("Fall:%d neck:%.2f/s hip:%.2f/s trunk:%.0f->%.0f inactive:%s",
+		e.TrackID, e.Evidence.NeckVelocity, e.Evidence.HipVelocity,
+		e.Evidence.UprightInclination, e.Evidence.LyingInclination, e.Evidence.Inactivity)
+}
+
+// sample represents measures of a human at time
+type sample struct {
+	time        time.Time
+	neck        openpose.Point
+	hip         openpose.Point
+	inclination float64
+	torso       float64
+}
+
+// phase represents fall detection phase of a track
+type phase int
+
+const (
+	monitoring phase = iota
+	fallen
+	alerted
+)
+
+// trackState represents fall detection state of a track
+type trackState struct {
+	samples  []sample
+	phase    phase
+	evidence Evidence
+	anchor   openpose.Point
+	still    time.Time
+	torso    float64
+}
+
+// Detector detects falls of tracked humans
+type Detector struct {
+	cfg    Config
+	tracks map[int]*trackState
+	mutex  sync.Mutex
+}
+
+// NewDetector returns a new Detector
+func NewDetector(cfg Config) *Detector {
+	return &Detector{
+		cfg:    cfg,
+		tracks: make(map[int]*trackState),
+	}
+}
+
+// Update feeds human of track in pixel space compared to img size (w, h) at t, returns fall events.
+// A fall is emitted once until the human is upright again
+func (d *Detector) Update(trackID int, h openpose.Human, imgW float64, imgH float64, t time.Time) []Event {
+	s, ok := newSample(h, imgW, imgH, t)
+	if !ok {
+		return nil
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	st, found := d.tracks[trackID]
+	if !found {
+		st = new(trackState)
+		d.tracks[trackID] = st
+	}
+	st.push(s, d.cfg.TransitionWindow+d.cfg.VelocitySpan)
+	switch st.phase {
+	case monitoring:
+		if evidence, detected := d.detect(st); detected {
+			st.phase = fallen
+			st.evidence = evidence
+			st.anchor = s.center()
+			st.still = t
+		}
+	case fallen:
+		if s.inclination <= d.cfg.UprightInclination {
+			st.phase = monitoring
+			return nil
+		}
+		if s.center().Distance(st.anchor) > d.cfg.InactiveDistance*st.torso {
+			st.anchor = s.center()
+			st.still = t
+			return nil
+		}
+		if t.Sub(st.still) >= d.cfg.InactivityDuration {
+			st.phase = alerted
+			st.evidence.Inactivity = t.Sub(st.still)
+			return []Event{{TrackID: trackID, Time: t, Evidence: st.evidence}}
+		}
+	case alerted:
+		if s.inclination <= d.cfg.UprightInclination {
+			st.phase = monitoring
+		}
+	}
+	return nil
+}
+
+// Forget drops state of track
+func (d *Detector) Forget(trackID int) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	delete(d.tracks, trackID)
+}
+
+// detect checks the latest sample of track ends a fast upright to lying transition
+func (d *Detector) detect(st *trackState) (Evidence, bool) {
+	last := st.samples[len(st.samples)-1]
+	if last.inclination < d.cfg.LyingInclination {
+		return Evidence{}, false
+	}
+	// the latest upright sample in transition window
+	upright := -1
+	for idx := len(st.samples) - 2; idx >= 0; idx-- {
+		if last.time.Sub(st.samples[idx].time) > d.cfg.TransitionWindow {
+			break
+		}
+		if st.samples[idx].inclination <= d.cfg.UprightInclination {
+			upright = idx
+			break
+		}
+	}
+	if upright < 0 {
+		return Evidence{}, false
+	}
+	// upright torso length is the scale, lying torso could be foreshortened
+	torso := st.samples[upright].torso
+	var neckVelocity, hipVelocity float64
+	for idx := upright + 1; idx < len(st.samples); idx++ {
+		cur := st.samples[idx]
+		from := -1
+		for j := idx - 1; j >= 0; j-- {
+			if cur.time.Sub(st.samples[j].time) >= d.cfg.VelocitySpan {
+				from = j
+				break
+			}
+		}
+		if from < 0 {
+			continue
+		}
+		prev := st.samples[from]
+		dt := cur.time.Sub(prev.time).Seconds()
+		// image y axis points down
+		if v := (cur.neck.Y - prev.neck.Y) / dt / torso; v > neckVelocity {
+			neckVelocity = v
+		}
+		if v := (cur.hip.Y - prev.hip.Y) / dt / torso; v > hipVelocity {
+			hipVelocity = v
+		}
+	}
+	if neckVelocity < d.cfg.MinDownwardVelocity || hipVelocity < d.cfg.MinDownwardVelocity {
+		return Evidence{}, false
+	}
+	st.torso = torso
+	return Evidence{
+		NeckVelocity:       neckVelocity,
+		HipVelocity:        hipVelocity,
+		UprightInclination: st.samples[upright].inclination,
+		LyingInclination:   last.inclination,
+		Impact:             last.time,
+	}, true
+}
+
+// push appends sample and drops samples older than window
+func (st *trackState) push(s sample, window time.Duration) {
+	st.samples = append(st.samples, s)
+	idx := 0
+	for idx < len(st.samples)-1 && s.time.Sub(st.samples[idx].time) > window {
+		idx++
+	}
+	st.samples = st.samples[idx:]
+}
+
+func newSample(h openpose.Human, imgW float64, imgH float64, t time.Time) (sample, bool) {
+	neck, foundNeck := h.PixelCenter([]openpose.CocoPart{openpose.CocoPartNeck}, imgW, imgH)
+	hip, foundHip := h.PixelCenter([]openpose.CocoPart{openpose.CocoPartRHip, openpose.CocoPartLHip}, imgW, imgH)
+	inclination, foundInclination := h.TrunkInclination(imgW, imgH)
+	if !foundNeck || !foundHip || !foundInclination {
+		return sample{}, false
+	}
+	torso := neck.Distance(hip)
+	if torso <= 0 {
+		return sample{}, false
+	}
+	return sample{
+		time:        t,
+		neck:        neck,
+		hip:         hip,
+		inclination: inclination,
+		torso:       torso,
+	}, true
+}
+
+// center returns mid point of neck and hip
+func (s sample) center() openpose.Point {
+	return s.neck.Add(s.hip).Mul(0.5)
+}
@@ -0,0 +1,111 @@
+package fall
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/internal/posetest"
+)
+
+const frameInterval = 33 * time.Millisecond
+
+// pose represents a synthetic body of mid hip in pixels and trunk inclination in degrees
+type pose struct {
+	hipY        float64
+	inclination float64
+}
+
+// newHuman returns a human of pose in a 640x480 image with 150 pixels torso
+func newHuman(p pose) openpose.Human {
+	rad := p.inclination * math.Pi / 180
+	hip := openpose.Pt(320, p.hipY)
+	neck := hip.Add(openpose.Pt(math.Sin(rad), -math.Cos(rad)).Mul(150))
+	side := openpose.Pt(math.Cos(rad), math.Sin(rad)).Mul(20)
+	points := map[openpose.CocoPart]openpose.Point{
+		openpose.CocoPartNeck: neck,
+		openpose.CocoPartRHip: hip.Sub(side),
+		openpose.CocoPartLHip: hip.Add(side),
+	}
+	return posetest.Human(points, 640, 480)
+}
+
+// sequence returns poses interpolated from a to b in duration
+func sequence(a pose, b pose, duration time.Duration) []pose {
+	frames := int(duration / frameInterval)
+	ret := make([]pose, frames)
+	for idx := range ret {
+		u := float64(idx+1) / float64(frames)
+		ret[idx] = pose{
+			hipY:        a.hipY + (b.hipY-a.hipY)*u,
+			inclination: a.inclination + (b.inclination-a.inclination)*u,
+		}
+	}
+	return ret
+}
+
+func run(d *Detector, poses ...[]pose) []Event {
+	var (
+		events []Event
+		t      = time.Now()
+	)
+	for _, seq := range poses {
+		for _, p := range seq {
+			events = append(events, d.Update(1, newHuman(p), 640, 480, t)...)
+			t = t.Add(frameInterval)
+		}
+	}
+	return events
+}
+
+var (
+	standing = pose{hipY: 300, inclination: 0}
+	sitting  = pose{hipY: 380, inclination: 10}
+	lying    = pose{hipY: 440, inclination: 90}
+)
+
+func TestDetector_Fall(t *testing.T) {
+	events := run(NewDetector(DefaultConfig()),
+		sequence(standing, standing, time.Second),
+		sequence(standing, lying, 600*time.Millisecond),
+		sequence(lying, lying, 4*time.Second),
+	)
+	if assert.Len(t, events, 1) {
+		event := events[0]
+		assert.Equal(t, 1, event.TrackID)
+		assert.Greater(t, event.Evidence.NeckVelocity, 1.0)
+		assert.Greater(t, event.Evidence.HipVelocity, 1.0)
+		assert.Greater(t, event.Evidence.LyingInclination, 60.0)
+		assert.True(t, event.Evidence.Inactivity >= DefaultConfig().InactivityDuration)
+		assert.Equal(t, event.Evidence.Impact.Add(event.Evidence.Inactivity), event.Time)
+	}
+}
+
+func TestDetector_NoFall(t *testing.T) {
+	cases := map[string][][]pose{
+		"lying down slowly": {
+			sequence(standing, standing, time.Second),
+			sequence(standing, sitting, 2*time.Second),
+			sequence(sitting, lying, 3*time.Second),
+			sequence(lying, lying, 4*time.Second),
+		},
+		"sitting down quickly": {
+			sequence(standing, standing, time.Second),
+			sequence(standing, sitting, 500*time.Millisecond),
+			sequence(sitting, sitting, 4*time.Second),
+		},
+		"getting up after the fall": {
+			sequence(standing, standing, time.Second),
+			sequence(standing, lying, 600*time.Millisecond),
+			sequence(lying, lying, time.Second),
+			sequence(lying, standing, time.Second),
+			sequence(standing, standing, 3*time.Second),
+		},
+	}
+	for name, poses := range cases {
+		assert.Empty(t, run(NewDetector(DefaultConfig()), poses...), name)
+	}
+}
@@ -0,0 +1,2 @@
+// Package fall detects falls of tracked humans
+package fall
@@ -0,0 +1,2 @@
+// Package gait analyses gait of side view pose sequences
+package gait
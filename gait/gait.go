@@ -0,0 +1,283 @@
+package gait
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/scale"
+)
+
+// Scaler converts pixels of a human to metres, e.g. scale.Scaler or a calibrated camera
+type Scaler interface {
+	// PixelsPerMetre returns pixels per metre at human in pixel space compared to img size (w, h)
+	PixelsPerMetre(h openpose.Human, imgW float64, imgH float64) (float64, bool)
+}
+
+// Config represents gait analysis config
+type Config struct {
+	// SmoothingWindow moving average window of trajectories in frames
+	SmoothingWindow int
+	// MinStepInterval min time between heel strikes of the same foot
+	MinStepInterval time.Duration
+	// Scaler optional pixel to metre scale, lengths in metres are reported with it
+	Scaler Scaler
+}
+
+// DefaultConfig returns default gait analysis config
+func DefaultConfig() Config {
+	return Config{
+		SmoothingWindow: 5,
+		MinStepInterval: 300 * time.Millisecond,
+	}
+}
+
+// Frame represents a human of the tracked sequence at time
+type Frame struct {
+	// Human human of the frame
+	Human openpose.Human
+	// Time time of the frame
+	Time time.Time
+}
+
+// Step represents a heel strike
+type Step struct {
+	// Side side of the striking foot
+	Side openpose.Side
+	// Frame index of the frame
+	Frame int
+	// Time of the heel strike
+	Time time.Time
+	// Length distance between ankles in body heights, 0 if unknown
+	Length float64
+	// LengthMetres distance between ankles in metres, 0 without scaler
+	LengthMetres float64
+}
+
+// Report represents gait analysis report
+type Report struct {
+	// Direction walking direction in image, 1 to the right and -1 to the left
+	Direction float64
+	// Steps heel strikes of both feet in time order
+	Steps []Step
+	// Cadence steps per minute
+	Cadence float64
+	// StrideTime mean time between heel strikes of the same foot
+	StrideTime time.Duration
+	// StepTime mean time from the opposite heel strike to the heel strike of each side
+	StepTime map[openpose.Side]time.Duration
+	// StepLength mean step length of each side in body heights
+	StepLength map[openpose.Side]float64
+	// StepLengthMetres mean step length of each side in metres, empty without scaler
+	StepLengthMetres map[openpose.Side]float64
+	// TimeSymmetry symmetry index of step time in percent, 0 is symmetric
+	TimeSymmetry float64
+	// LengthSymmetry symmetry index of step length in percent, 0 is symmetric
+	LengthSymmetry float64
+}
+
+// sideAnkles represents ankle of each side
+var sideAnkles = map[openpose.Side]openpose.CocoPart{
+	openpose.SideRight: openpose.CocoPartRAnkle,
+	openpose.SideLeft:  openpose.CocoPartLAnkle,
+}
+
+// Analyze returns gait report of frames in time order in pixel space compared to img size (w, h).
+// Heel strikes are the moments a foot is the farthest in front of the hips
+func Analyze(frames []Frame, imgW float64, imgH float64, cfg Config) Report {
+	ret := Report{
+		StepTime:         make(map[openpose.Side]time.Duration, 2),
+		StepLength:       make(map[openpose.Side]float64, 2),
+		StepLengthMetres: make(map[openpose.Side]float64, 2),
+	}
+	if len(frames) < 3 {
+		return ret
+	}
+	ret.Direction = direction(frames, imgW, imgH)
+	for side, ankle := range sideAnkles {
+		// foot position in front of the hips along walking direction
+		signal := make([]float64, len(frames))
+		valid := make([]bool, len(frames))
+		for idx, frame := range frames {
+			a, foundAnkle := frame.Human.PixelCenter([]openpose.CocoPart{ankle}, imgW, imgH)
+			hip, foundHip := frame.Human.PixelCenter([]openpose.CocoPart{openpose.CocoPartRHip, openpose.CocoPartLHip}, imgW, imgH)
+			if foundAnkle && foundHip {
+				signal[idx] = (a.X - hip.X) * ret.Direction
+				valid[idx] = true
+			}
+		}
+		signal = smooth(signal, valid, cfg.SmoothingWindow)
+		for _, idx := range peaks(signal, valid, frames, cfg.MinStepInterval) {
+			ret.Steps = append(ret.Steps, newStep(frames[idx].Human, side, idx, frames[idx].Time, imgW, imgH, cfg.Scaler))
+		}
+	}
+	sort.SliceStable(ret.Steps, func(i, j int) bool { return ret.Steps[i].Frame < ret.Steps[j].Frame })
+	ret.summarize()
+	return ret
+}
+
+// summarize computes temporal and spatial parameters of steps
+func (r *Report) summarize() {
+	if len(r.Steps) < 2 {
+		return
+	}
+	if span := r.Steps[len(r.Steps)-1].Time.Sub(r.Steps[0].Time).Minutes(); span > 0 {
+		r.Cadence = float64(len(r.Steps)-1) / span
+	}
+	var (
+		strideSum   time.Duration
+		strideCount int
+		stepSum     = make(map[openpose.Side]time.Duration, 2)
+		stepCount   = make(map[openpose.Side]int, 2)
+		lengthSum   = make(map[openpose.Side]float64, 2)
+		lengthCount = make(map[openpose.Side]float64, 2)
+		metreSum    = make(map[openpose.Side]float64, 2)
+		metreCount  = make(map[openpose.Side]float64, 2)
+		last        = make(map[openpose.Side]Step, 2)
+	)
+	for idx, step := range r.Steps {
+		if prev, found := last[step.Side]; found {
+			strideSum += step.Time.Sub(prev.Time)
+			strideCount++
+		}
+		last[step.Side] = step
+		if idx > 0 && r.Steps[idx-1].Side != step.Side {
+			stepSum[step.Side] += step.Time.Sub(r.Steps[idx-1].Time)
+			stepCount[step.Side]++
+		}
+		if step.Length > 0 {
+			lengthSum[step.Side] += step.Length
+			lengthCount[step.Side]++
+		}
+		if step.LengthMetres > 0 {
+			metreSum[step.Side] += step.LengthMetres
+			metreCount[step.Side]++
+		}
+	}
+	if strideCount > 0 {
+		r.StrideTime = strideSum / time.Duration(strideCount)
+	}
+	for side := range sideAnkles {
+		if stepCount[side] > 0 {
+			r.StepTime[side] = stepSum[side] / time.Duration(stepCount[side])
+		}
+		if lengthCount[side] > 0 {
+			r.StepLength[side] = lengthSum[side] / lengthCount[side]
+		}
+		if metreCount[side] > 0 {
+			r.StepLengthMetres[side] = metreSum[side] / metreCount[side]
+		}
+	}
+	r.TimeSymmetry = symmetryIndex(r.StepTime[openpose.SideRight].Seconds(), r.StepTime[openpose.SideLeft].Seconds())
+	r.LengthSymmetry = symmetryIndex(r.StepLength[openpose.SideRight], r.StepLength[openpose.SideLeft])
+}
+
+// newStep returns heel strike of side at frame human
+func newStep(h openpose.Human, side openpose.Side, frame int, t time.Time, imgW float64, imgH float64, scaler Scaler) Step {
+	step := Step{Side: side, Frame: frame, Time: t}
+	r, foundR := h.PixelCenter([]openpose.CocoPart{openpose.CocoPartRAnkle}, imgW, imgH)
+	l, foundL := h.PixelCenter([]openpose.CocoPart{openpose.CocoPartLAnkle}, imgW, imgH)
+	if !foundR || !foundL {
+		return step
+	}
+	distance := math.Abs(r.X - l.X)
+	if height, found := scale.EstimateHeight(h, imgW, imgH); found {
+		step.Length = distance / height.Pixels
+	}
+	if scaler != nil {
+		if ppm, found := scaler.PixelsPerMetre(h, imgW, imgH); found && ppm > 0 {
+			step.LengthMetres = distance / ppm
+		}
+	}
+	return step
+}
+
+// direction returns walking direction from hip displacement, or facing direction of the nose if the hips stay in place
+func direction(frames []Frame, imgW float64, imgH float64) float64 {
+	var (
+		first, last openpose.Point
+		found       bool
+		torso       float64
+		facing      float64
+		hipPart     = []openpose.CocoPart{openpose.CocoPartRHip, openpose.CocoPartLHip}
+		neckPart    = []openpose.CocoPart{openpose.CocoPartNeck}
+		nosePart    = []openpose.CocoPart{openpose.CocoPartNose}
+	)
+	for _, frame := range frames {
+		hip, foundHip := frame.Human.PixelCenter(hipPart, imgW, imgH)
+		if foundHip {
+			if !found {
+				first, found = hip, true
+			}
+			last = hip
+		}
+		if length, ok := frame.Human.TorsoLength(imgW, imgH); ok {
+			torso = math.Max(torso, length)
+		}
+		nose, foundNose := frame.Human.PixelCenter(nosePart, imgW, imgH)
+		neck, foundNeck := frame.Human.PixelCenter(neckPart, imgW, imgH)
+		if foundNose && foundNeck {
+			facing += nose.X - neck.X
+		}
+	}
+	if dx := last.X - first.X; found && math.Abs(dx) > torso {
+		return math.Copysign(1, dx)
+	}
+	if facing < 0 {
+		return -1
+	}
+	return 1
+}
+
+// smooth returns moving average of valid values in window
+func smooth(values []float64, valid []bool, window int) []float64 {
+	if window <= 1 {
+		return values
+	}
+	ret := make([]float64, len(values))
+	half := window / 2
+	for idx := range values {
+		var sum, count float64
+		for j := idx - half; j <= idx+half; j++ {
+			if j < 0 || j >= len(values) || !valid[j] {
+				continue
+			}
+			sum += values[j]
+			count++
+		}
+		if count > 0 {
+			ret[idx] = sum / count
+		}
+	}
+	return ret
+}
+
+// peaks returns indices of positive local maxima of valid values at least minInterval apart, the higher peak wins
+func peaks(values []float64, valid []bool, frames []Frame, minInterval time.Duration) []int {
+	var ret []int
+	for idx := 1; idx < len(values)-1; idx++ {
+		if !valid[idx] || !valid[idx-1] || !valid[idx+1] || values[idx] <= 0 {
+			continue
+		}
+		if values[idx] < values[idx-1] || values[idx] <= values[idx+1] {
+			continue
+		}
+		if n := len(ret); n > 0 && frames[idx].Time.Sub(frames[ret[n-1]].Time) < minInterval {
+			if values[idx] > values[ret[n-1]] {
+				ret[n-1] = idx
+			}
+			continue
+		}
+		ret = append(ret, idx)
+	}
+	return ret
+}
+
+// symmetryIndex returns |a - b| / mean(a, b) in percent
+func symmetryIndex(a float64, b float64) float64 {
+	if a+b == 0 {
+		return 0
+	}
+	return math.Abs(a-b) / ((a + b) / 2) * 100
+}
@@ -0,0 +1,73 @@
+package gait
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/internal/posetest"
+)
+
+type fixedScaler float64
+
+func (s fixedScaler) PixelsPerMetre(h openpose.Human, imgW float64, imgH float64) (float64, bool) {
+	return float64(s), true
+}
+
+// walk returns side view frames of a human walking to the right at 100 pixels per second in a 1280x480 image
+// with stride frequency in Hz, ankles swinging by amplitude in pixels and the left foot lagging by phase
+func walk(seconds float64, frequency float64, amplitude float64, phase float64) []Frame {
+	const fps = 30
+	start := time.Now()
+	frames := make([]Frame, int(seconds*fps))
+	for idx := range frames {
+		t := float64(idx) / fps
+		hipX := 200 + 100*t
+		points := map[openpose.CocoPart]openpose.Point{
+			openpose.CocoPartNose: openpose.Pt(hipX+10, 30),
+			openpose.CocoPartNeck: openpose.Pt(hipX, 70),
+			openpose.CocoPartRHip: openpose.Pt(hipX, 190),
+			openpose.CocoPartLHip: openpose.Pt(hipX, 190),
+		}
+		for _, side := range []struct {
+			knee  openpose.CocoPart
+			ankle openpose.CocoPart
+			phase float64
+		}{
+			{openpose.CocoPartRKnee, openpose.CocoPartRAnkle, 0},
+			{openpose.CocoPartLKnee, openpose.CocoPartLAnkle, phase},
+		} {
+			dx := amplitude * math.Sin(2*math.Pi*frequency*t-side.phase)
+			ankleY := 190 + math.Sqrt(200*200-dx*dx)
+			points[side.ankle] = openpose.Pt(hipX+dx, ankleY)
+			points[side.knee] = openpose.Pt(hipX+dx/2, (190+ankleY)/2)
+		}
+		frames[idx] = Frame{Human: posetest.Human(points, 1280, 480), Time: start.Add(time.Duration(t * float64(time.Second)))}
+	}
+	return frames
+}
+
+func TestAnalyze(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Scaler = fixedScaler(200)
+	report := Analyze(walk(6, 0.9, 40, math.Pi), 1280, 480, cfg)
+	assert.Equal(t, 1.0, report.Direction)
+	assert.InDelta(t, 108, report.Cadence, 3)
+	assert.InDelta(t, 1/0.9, report.StrideTime.Seconds(), 0.05)
+	assert.Less(t, report.TimeSymmetry, 5.0)
+	assert.Less(t, report.LengthSymmetry, 5.0)
+	// 80 pixels of about 410 pixels body height
+	assert.InDelta(t, 80.0/410, report.StepLength[openpose.SideRight], 0.02)
+	assert.InDelta(t, 0.4, report.StepLengthMetres[openpose.SideLeft], 0.02)
+	for idx := 1; idx < len(report.Steps); idx++ {
+		assert.NotEqual(t, report.Steps[idx-1].Side, report.Steps[idx].Side)
+	}
+
+	// left heel strikes sooner after the right one than the other way round
+	limping := Analyze(walk(6, 0.9, 40, 0.7*math.Pi), 1280, 480, cfg)
+	assert.Greater(t, limping.TimeSymmetry, 20.0)
+	assert.Less(t, limping.StepTime[openpose.SideLeft].Seconds(), limping.StepTime[openpose.SideRight].Seconds())
+}
@@ -1,5 +1,7 @@
 package openpose
 
+import "math"
+
 // Point represents coordinate
 type Point struct {
 	// X coordinate of body part
@@ -18,6 +20,41 @@ func (p Point) IsZero() bool {
 	return p.X <= 1e-15 && p.Y <= 1e-15
 }
 
+// Add returns the vector p+q
+func (p Point) Add(q Point) Point {
+	return Point{p.X + q.X, p.Y + q.Y}
+}
+
+// Sub returns the vector p-q
+func (p Point) Sub(q Point) Point {
+	return Point{p.X - q.X, p.Y - q.Y}
+}
+
+// Mul returns the vector p*k
+func (p Point) Mul(k float64) Point {
+	return Point{p.X * k, p.Y * k}
+}
+
+// Scale returns the point scaled by (w, h), converts normalized coordinate to pixel space
+func (p Point) Scale(w float64, h float64) Point {
+	return Point{p.X * w, p.Y * h}
+}
+
+// Dot returns the dot product of p and q
+func (p Point) Dot(q Point) float64 {
+	return p.X*q.X + p.Y*q.Y
+}
+
+// Norm returns the length of vector p
+func (p Point) Norm() float64 {
+	return math.Hypot(p.X, p.Y)
+}
+
+// Distance returns the euclidean distance between p and q
+func (p Point) Distance(q Point) float64 {
+	return p.Sub(q).Norm()
+}
+
 // ZP represents zero position point
 var ZP = Point{0, 0}
 
@@ -37,19 +74,39 @@ func Rect(x, y, w, h int) Rectangle {
 // Intersect returns the largest rectangle contained by both r and s. If the
 // two rectangles do not overlap then the zero rectangle will be returned.
 func (r Rectangle) Intersect(s Rectangle) Rectangle {
-	if r.X < s.X {
-		r.X = s.X
+	x0, y0 := r.X, r.Y
+	x1, y1 := r.X+r.W, r.Y+r.H
+	if x0 < s.X {
+		x0 = s.X
+	}
+	if y0 < s.Y {
+		y0 = s.Y
 	}
-	if r.Y < s.Y {
-		r.Y = s.Y
+	if x1 > s.X+s.W {
+		x1 = s.X + s.W
 	}
-	if r.X+r.W > s.X+s.W {
-		r.W = s.X + s.W - r.X
+	if y1 > s.Y+s.H {
+		y1 = s.Y + s.H
 	}
-	if r.Y+r.H > s.Y+s.W {
-		r.H = s.Y + s.H - r.H
+	if x1 <= x0 || y1 <= y0 {
+		return ZR
 	}
-	return r
+	return Rect(x0, y0, x1-x0, y1-y0)
+}
+
+// IoU returns intersection over union of r and s
+func (r Rectangle) IoU(s Rectangle) float64 {
+	inter := r.Intersect(s).Area()
+	union := r.Area() + s.Area() - inter
+	if union <= 0 {
+		return 0
+	}
+	return float64(inter) / float64(union)
+}
+
+// Center returns center point of rectangle
+func (r Rectangle) Center() Point {
+	return Pt(float64(r.X)+float64(r.W)/2, float64(r.Y)+float64(r.H)/2)
 }
 
 // Area returns rectangle area size
@@ -0,0 +1,27 @@
+package openpose
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRectangle_Intersect(t *testing.T) {
+	tests := []struct {
+		r    Rectangle
+		s    Rectangle
+		want Rectangle
+	}{
+		{Rect(0, 0, 10, 10), Rect(5, 5, 10, 10), Rect(5, 5, 5, 5)},
+		{Rect(5, 5, 10, 10), Rect(0, 0, 10, 10), Rect(5, 5, 5, 5)},
+		// s inside r, width and height of s differ
+		{Rect(0, 0, 10, 10), Rect(2, 3, 4, 6), Rect(2, 3, 4, 6)},
+		{Rect(0, 0, 20, 10), Rect(15, 2, 10, 3), Rect(15, 2, 5, 3)},
+		{Rect(0, 0, 10, 10), Rect(10, 0, 10, 10), ZR},
+		{Rect(0, 0, 10, 10), Rect(20, 20, 5, 5), ZR},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.r.Intersect(tt.s), "%v & %v", tt.r, tt.s)
+	}
+	assert.InDelta(t, 25.0/175, Rect(0, 0, 10, 10).IoU(Rect(5, 5, 10, 10)), 1e-9)
+}
@@ -8,6 +8,7 @@ require (
 	github.com/llgcode/draw2d v0.0.0-20210904075650-80aa0a2a901d
 	github.com/stretchr/testify v1.6.1
 	github.com/tensorflow/tensorflow v1.15.5
+	golang.org/x/image v0.0.0-20191009234506-e7c1f5e7dbb8
 	gorgonia.org/tensor v0.9.21
 	nhooyr.io/websocket v1.8.7
 )
@@ -30,7 +31,6 @@ require (
 	github.com/xtgo/set v1.0.0 // indirect
 	go4.org/unsafe/assume-no-moving-gc v0.0.0-20201222180813-1025295fd063 // indirect
 	gocv.io/x/gocv v0.28.0 // indirect
-	golang.org/x/image v0.0.0-20191009234506-e7c1f5e7dbb8 // indirect
 	golang.org/x/sys v0.0.0-20201107080550-4d91cf3a1aaf // indirect
 	golang.org/x/xerrors v0.0.0-20191204190536-9bdfabe68543 // indirect
 	gonum.org/v1/gonum v0.8.2 // indirect
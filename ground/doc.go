@@ -0,0 +1,2 @@
+// Package ground maps where people stand to floor plan coordinates by ground plane homography
+package ground
@@ -0,0 +1,215 @@
+package ground
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"os"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/calibration"
+)
+
+// Correspondence represents an image point and its floor plan point, shared with the calibration file
+type Correspondence = calibration.Correspondence
+
+// LoadCorrespondences loads correspondences from a JSON array
+func LoadCorrespondences(r io.Reader) ([]Correspondence, error) {
+	var ret []Correspondence
+	if err := json.NewDecoder(r).Decode(&ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// LoadCorrespondencesFile loads correspondences from a JSON file
+func LoadCorrespondencesFile(path string) ([]Correspondence, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	return LoadCorrespondences(fd)
+}
+
+// Homography represents a 3x3 planar projective transform
+type Homography [3][3]float64
+
+// NewHomography returns homography mapping image points to floor points of at least 4 correspondences.
+// More than 4 correspondences are fitted by least squares
+func NewHomography(pairs []Correspondence) (Homography, error) {
+	if len(pairs) < 4 {
+		return Homography{}, errors.New("at least 4 correspondences required")
+	}
+	src := make([]openpose.Point, len(pairs))
+	dst := make([]openpose.Point, len(pairs))
+	for idx, pair := range pairs {
+		src[idx], dst[idx] = pair.Image, pair.Floor
+	}
+	// normalize both point sets for numerical stability, see Hartley, In defense of the eight-point algorithm
+	srcT, srcOK := normalization(src)
+	dstT, dstOK := normalization(dst)
+	if !srcOK || !dstOK {
+		return Homography{}, errors.New("degenerate correspondences")
+	}
+	// DLT with h33 = 1, solved by normal equations of the 2n x 8 system
+	var (
+		ata [8][8]float64
+		atb [8]float64
+	)
+	for idx := range pairs {
+		s, _ := srcT.Project(src[idx])
+		d, _ := dstT.Project(dst[idx])
+		rows := [2][8]float64{
+			{s.X, s.Y, 1, 0, 0, 0, -d.X * s.X, -d.X * s.Y},
+			{0, 0, 0, s.X, s.Y, 1, -d.Y * s.X, -d.Y * s.Y},
+		}
+		rhs := [2]float64{d.X, d.Y}
+		for r, row := range rows {
+			for i := 0; i < 8; i++ {
+				for j := 0; j < 8; j++ {
+					ata[i][j] += row[i] * row[j]
+				}
+				atb[i] += row[i] * rhs[r]
+			}
+		}
+	}
+	x, ok := solve(ata, atb)
+	if !ok {
+		return Homography{}, errors.New("degenerate correspondences")
+	}
+	normalized := Homography{
+		{x[0], x[1], x[2]},
+		{x[3], x[4], x[5]},
+		{x[6], x[7], 1},
+	}
+	dstInv, _ := dstT.Inverse()
+	return dstInv.Mul(normalized).Mul(srcT).normalize(), nil
+}
+
+// W returns homogeneous scale of p transformed by homography, its sign tells the side of the vanishing line p is on
+func (h Homography) W(p openpose.Point) float64 {
+	return h[2][0]*p.X + h[2][1]*p.Y + h[2][2]
+}
+
+// Project returns p transformed by homography, false if p maps to infinity
+func (h Homography) Project(p openpose.Point) (openpose.Point, bool) {
+	w := h.W(p)
+	if math.Abs(w) <= 1e-12 {
+		return openpose.ZP, false
+	}
+	return openpose.Pt(
+		(h[0][0]*p.X+h[0][1]*p.Y+h[0][2])/w,
+		(h[1][0]*p.X+h[1][1]*p.Y+h[1][2])/w,
+	), true
+}
+
+// Mul returns the homography applying o then h
+func (h Homography) Mul(o Homography) Homography {
+	var ret Homography
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				ret[i][j] += h[i][k] * o[k][j]
+			}
+		}
+	}
+	return ret
+}
+
+// Inverse returns inverse homography, false if h is singular
+func (h Homography) Inverse() (Homography, bool) {
+	var ret Homography
+	// adjugate divided by determinant
+	ret[0][0] = h[1][1]*h[2][2] - h[1][2]*h[2][1]
+	ret[0][1] = h[0][2]*h[2][1] - h[0][1]*h[2][2]
+	ret[0][2] = h[0][1]*h[1][2] - h[0][2]*h[1][1]
+	ret[1][0] = h[1][2]*h[2][0] - h[1][0]*h[2][2]
+	ret[1][1] = h[0][0]*h[2][2] - h[0][2]*h[2][0]
+	ret[1][2] = h[0][2]*h[1][0] - h[0][0]*h[1][2]
+	ret[2][0] = h[1][0]*h[2][1] - h[1][1]*h[2][0]
+	ret[2][1] = h[0][1]*h[2][0] - h[0][0]*h[2][1]
+	ret[2][2] = h[0][0]*h[1][1] - h[0][1]*h[1][0]
+	det := h[0][0]*ret[0][0] + h[0][1]*ret[1][0] + h[0][2]*ret[2][0]
+	if math.Abs(det) <= 1e-15 {
+		return Homography{}, false
+	}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			ret[i][j] /= det
+		}
+	}
+	return ret.normalize(), true
+}
+
+// normalize scales h so that h33 is 1 when possible
+func (h Homography) normalize() Homography {
+	if math.Abs(h[2][2]) <= 1e-15 {
+		return h
+	}
+	s := h[2][2]
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			h[i][j] /= s
+		}
+	}
+	return h
+}
+
+// normalization returns similarity moving centroid of points to origin with mean distance of sqrt(2)
+func normalization(points []openpose.Point) (Homography, bool) {
+	var center openpose.Point
+	for _, p := range points {
+		center = center.Add(p)
+	}
+	center = center.Mul(1 / float64(len(points)))
+	var dist float64
+	for _, p := range points {
+		dist += p.Distance(center)
+	}
+	dist /= float64(len(points))
+	if dist <= 1e-12 {
+		return Homography{}, false
+	}
+	s := math.Sqrt2 / dist
+	return Homography{
+		{s, 0, -s * center.X},
+		{0, s, -s * center.Y},
+		{0, 0, 1},
+	}, true
+}
+
+// solve solves a x = b by Gaussian elimination with partial pivoting
+func solve(a [8][8]float64, b [8]float64) ([8]float64, bool) {
+	var x [8]float64
+	const n = 8
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) <= 1e-12 {
+			return x, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+		for row := col + 1; row < n; row++ {
+			f := a[row][col] / a[col][col]
+			for k := col; k < n; k++ {
+				a[row][k] -= f * a[col][k]
+			}
+			b[row] -= f * b[col]
+		}
+	}
+	for row := n - 1; row >= 0; row-- {
+		sum := b[row]
+		for k := row + 1; k < n; k++ {
+			sum -= a[row][k] * x[k]
+		}
+		x[row] = sum / a[row][row]
+	}
+	return x, true
+}
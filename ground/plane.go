@@ -0,0 +1,230 @@
+package ground
+
+import (
+	"errors"
+	"math"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/calibration"
+	"github.com/bububa/openpose/scale"
+)
+
+// Contact represents which body parts the ground contact point comes from
+type Contact int
+
+const (
+	// ContactAnkles midpoint of the visible ankles
+	ContactAnkles Contact = iota
+	// ContactKnees extrapolated below the knees by shin length
+	ContactKnees
+	// ContactHips extrapolated below the hips by leg length
+	ContactHips
+)
+
+// String returns Contact name
+func (c Contact) String() string {
+	switch c {
+	case ContactAnkles:
+		return "ankles"
+	case ContactKnees:
+		return "knees"
+	case ContactHips:
+		return "hips"
+	}
+	return "unknown"
+}
+
+// Body height ratios below knee and hip joints to the floor, Drillis and Contini
+const (
+	kneeHeightRatio = 0.285
+	hipHeightRatio  = 0.530
+)
+
+// GroundPoint returns ground contact point of human in pixels compared to img size (w, h).
+// It is the ankle midpoint, or extrapolated straight down from the knees or the hips by
+// anthropometric leg length when the feet are hidden
+func GroundPoint(h openpose.Human, imgW float64, imgH float64) (openpose.Point, Contact, bool) {
+	if p, found := h.PixelCenter([]openpose.CocoPart{openpose.CocoPartRAnkle, openpose.CocoPartLAnkle}, imgW, imgH); found {
+		return p, ContactAnkles, true
+	}
+	height, found := scale.EstimateHeight(h, imgW, imgH)
+	if !found {
+		return openpose.ZP, ContactHips, false
+	}
+	if p, found := h.PixelCenter([]openpose.CocoPart{openpose.CocoPartRKnee, openpose.CocoPartLKnee}, imgW, imgH); found {
+		return openpose.Pt(p.X, p.Y+height.Pixels*kneeHeightRatio), ContactKnees, true
+	}
+	if p, found := h.PixelCenter([]openpose.CocoPart{openpose.CocoPartRHip, openpose.CocoPartLHip}, imgW, imgH); found {
+		return openpose.Pt(p.X, p.Y+height.Pixels*hipHeightRatio), ContactHips, true
+	}
+	return openpose.ZP, ContactHips, false
+}
+
+// Position represents where a human stands
+type Position struct {
+	// Image ground contact point in pixels
+	Image openpose.Point
+	// Floor ground contact point in floor plan units
+	Floor openpose.Point
+	// Contact body parts the ground contact point comes from
+	Contact Contact
+}
+
+// Plane maps between image and floor plan coordinates of the ground plane
+type Plane struct {
+	toFloor Homography
+	toImage Homography
+	// floorSide and imageSide signs of homogeneous scale of correspondences mapped to floor and to image,
+	// points mapping with the opposite sign are beyond the horizon or behind the camera
+	floorSide float64
+	imageSide float64
+	pairs     []Correspondence
+	// camera undistorts image points before mapping, optional
+	camera *calibration.Camera
+}
+
+// NewPlane returns a new Plane of at least 4 image to floor correspondences
+func NewPlane(pairs []Correspondence) (*Plane, error) {
+	toFloor, err := NewHomography(pairs)
+	if err != nil {
+		return nil, err
+	}
+	toImage, ok := toFloor.Inverse()
+	if !ok {
+		return nil, errors.New("singular homography")
+	}
+	floorSide, imageSide := side(toFloor, pairs, false), side(toImage, pairs, true)
+	if floorSide == 0 || imageSide == 0 {
+		return nil, errors.New("correspondences on both sides of the horizon")
+	}
+	return &Plane{
+		toFloor:   toFloor,
+		toImage:   toImage,
+		floorSide: floorSide,
+		imageSide: imageSide,
+		pairs:     append([]Correspondence(nil), pairs...),
+	}, nil
+}
+
+// side returns sign of homogeneous scale of image points, or floor points if floor, of pairs mapped by h, 0 if signs differ
+func side(h Homography, pairs []Correspondence, floor bool) float64 {
+	var ret float64
+	for _, pair := range pairs {
+		pt := pair.Image
+		if floor {
+			pt = pair.Floor
+		}
+		sign := math.Copysign(1, h.W(pt))
+		if ret != 0 && sign != ret {
+			return 0
+		}
+		ret = sign
+	}
+	return ret
+}
+
+// project returns pt mapped by h, false if it is on or beyond the vanishing line relative to the correspondences on side
+func project(h Homography, sideSign float64, pt openpose.Point) (openpose.Point, bool) {
+	if h.W(pt)*sideSign <= 1e-12 {
+		return openpose.ZP, false
+	}
+	return h.Project(pt)
+}
+
+// NewCameraPlane returns a new Plane of floor correspondences of calibrated camera.
+// Image points are undistorted before mapping, so straight floor lines stay straight with wide angle lenses
+func NewCameraPlane(cam calibration.Camera) (*Plane, error) {
+	pairs := make([]Correspondence, 0, len(cam.Floor))
+	for _, pair := range cam.Floor {
+		pair.Image = cam.Undistort(pair.Image)
+		pairs = append(pairs, pair)
+	}
+	plane, err := NewPlane(pairs)
+	if err != nil {
+		return nil, err
+	}
+	plane.pairs = cam.Floor
+	plane.camera = &cam
+	return plane, nil
+}
+
+// Homography returns homography mapping image points to floor points
+func (p *Plane) Homography() Homography {
+	return p.toFloor
+}
+
+// ToFloor returns floor point of image point in pixels, false if it is on or above the horizon
+func (p *Plane) ToFloor(pt openpose.Point) (openpose.Point, bool) {
+	if p.camera != nil {
+		pt = p.camera.Undistort(pt)
+	}
+	return project(p.toFloor, p.floorSide, pt)
+}
+
+// ToImage returns image point in pixels of floor point
+func (p *Plane) ToImage(pt openpose.Point) (openpose.Point, bool) {
+	ret, ok := project(p.toImage, p.imageSide, pt)
+	if ok && p.camera != nil {
+		ret = p.camera.Distort(ret)
+	}
+	return ret, ok
+}
+
+// ReprojectionError returns root mean square floor distance between mapped image points and floor points of the correspondences
+func (p *Plane) ReprojectionError() float64 {
+	var sum float64
+	for _, pair := range p.pairs {
+		floor, ok := p.ToFloor(pair.Image)
+		if !ok {
+			return math.Inf(1)
+		}
+		d := floor.Distance(pair.Floor)
+		sum += d * d
+	}
+	return math.Sqrt(sum / float64(len(p.pairs)))
+}
+
+// Locate returns position of human in pixel space compared to img size (w, h).
+// A camera plane undistorts the human first and maps img size to the calibrated image size
+func (p *Plane) Locate(h openpose.Human, imgW float64, imgH float64) (Position, bool) {
+	if p.camera == nil {
+		return p.locate(h, imgW, imgH)
+	}
+	pos, found := p.locate(p.camera.UndistortHuman(h), p.camera.Width, p.camera.Height)
+	if found {
+		pos.Image = p.camera.Distort(pos.Image).Scale(imgW/p.camera.Width, imgH/p.camera.Height)
+	}
+	return pos, found
+}
+
+// locate returns position of human in pixel space compared to img size (w, h) of the homography
+func (p *Plane) locate(h openpose.Human, imgW float64, imgH float64) (Position, bool) {
+	img, contact, found := GroundPoint(h, imgW, imgH)
+	if !found {
+		return Position{}, false
+	}
+	floor, ok := project(p.toFloor, p.floorSide, img)
+	if !ok {
+		return Position{}, false
+	}
+	return Position{
+		Image:   img,
+		Floor:   floor,
+		Contact: contact,
+	}, true
+}
+
+// LocateAll returns positions of humans, the index of each position in humans is returned along
+func (p *Plane) LocateAll(humans []openpose.Human, imgW float64, imgH float64) ([]Position, []int) {
+	var (
+		positions []Position
+		indices   []int
+	)
+	for idx, h := range humans {
+		if pos, found := p.Locate(h, imgW, imgH); found {
+			positions = append(positions, pos)
+			indices = append(indices, idx)
+		}
+	}
+	return positions, indices
+}
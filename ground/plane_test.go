@@ -0,0 +1,159 @@
+package ground
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/calibration"
+	"github.com/bububa/openpose/internal/posetest"
+)
+
+// camera is a synthetic floor to image homography of a tilted camera
+var camera = Homography{
+	{80, 20, 320},
+	{-5, 10, 100},
+	{0.01, 0.2, 1},
+}
+
+func correspondences(t *testing.T, floor ...openpose.Point) []Correspondence {
+	ret := make([]Correspondence, 0, len(floor))
+	for _, p := range floor {
+		img, ok := camera.Project(p)
+		assert.True(t, ok)
+		ret = append(ret, Correspondence{Image: img, Floor: p})
+	}
+	return ret
+}
+
+func TestNewHomography(t *testing.T) {
+	pairs := correspondences(t, openpose.Pt(0, 0), openpose.Pt(4, 0), openpose.Pt(4, 6), openpose.Pt(0, 6), openpose.Pt(2, 3))
+	plane, err := NewPlane(pairs)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.InDelta(t, 0, plane.ReprojectionError(), 1e-6)
+	for _, floor := range []openpose.Point{openpose.Pt(1, 1), openpose.Pt(3.5, 5), openpose.Pt(-1, 8)} {
+		img, _ := camera.Project(floor)
+		got, ok := plane.ToFloor(img)
+		assert.True(t, ok)
+		assert.InDelta(t, floor.X, got.X, 1e-6)
+		assert.InDelta(t, floor.Y, got.Y, 1e-6)
+		back, _ := plane.ToImage(got)
+		assert.InDelta(t, img.X, back.X, 1e-6)
+		assert.InDelta(t, img.Y, back.Y, 1e-6)
+	}
+	_, err = NewHomography(pairs[:3])
+	assert.Error(t, err)
+	_, err = NewHomography(correspondences(t, openpose.Pt(0, 0), openpose.Pt(1, 0), openpose.Pt(2, 0), openpose.Pt(3, 0)))
+	assert.Error(t, err)
+}
+
+func TestPlane_Horizon(t *testing.T) {
+	plane, err := NewPlane(correspondences(t, openpose.Pt(0, 0), openpose.Pt(4, 0), openpose.Pt(4, 6), openpose.Pt(0, 6)))
+	if !assert.NoError(t, err) {
+		return
+	}
+	// floor point (0, -10) is behind the camera, its image is beyond the vanishing line and must not map back to the floor
+	img := openpose.Pt(-120, 0)
+	assert.Less(t, camera.W(openpose.Pt(0, -10)), 0.0)
+	_, ok := plane.ToFloor(img)
+	assert.False(t, ok)
+	_, ok = plane.ToImage(openpose.Pt(0, -10))
+	assert.False(t, ok)
+	_, ok = plane.ToFloor(openpose.Pt(300, 400))
+	assert.True(t, ok)
+}
+
+func TestLoadCorrespondences(t *testing.T) {
+	pairs, err := LoadCorrespondences(strings.NewReader(`[{"image": {"X": 10, "Y": 20}, "floor": {"X": 1, "Y": 2}}]`))
+	if assert.NoError(t, err) && assert.Len(t, pairs, 1) {
+		assert.Equal(t, openpose.Pt(10, 20), pairs[0].Image)
+		assert.Equal(t, openpose.Pt(1, 2), pairs[0].Floor)
+	}
+}
+
+func TestPlane_Locate(t *testing.T) {
+	plane, err := NewPlane(correspondences(t, openpose.Pt(0, 0), openpose.Pt(4, 0), openpose.Pt(4, 6), openpose.Pt(0, 6)))
+	if !assert.NoError(t, err) {
+		return
+	}
+	points := map[openpose.CocoPart]openpose.Point{
+		openpose.CocoPartNose:      openpose.Pt(300, 100),
+		openpose.CocoPartNeck:      openpose.Pt(300, 140),
+		openpose.CocoPartRShoulder: openpose.Pt(270, 140),
+		openpose.CocoPartLShoulder: openpose.Pt(330, 140),
+		openpose.CocoPartRHip:      openpose.Pt(290, 260),
+		openpose.CocoPartLHip:      openpose.Pt(310, 260),
+		openpose.CocoPartRKnee:     openpose.Pt(290, 360),
+		openpose.CocoPartLKnee:     openpose.Pt(310, 360),
+		openpose.CocoPartRAnkle:    openpose.Pt(290, 460),
+		openpose.CocoPartLAnkle:    openpose.Pt(310, 460),
+	}
+	h := posetest.Human(points, 640, 480)
+	pos, found := plane.Locate(h, 640, 480)
+	if assert.True(t, found) {
+		assert.Equal(t, ContactAnkles, pos.Contact)
+		assert.InDelta(t, 300, pos.Image.X, 1e-6)
+		assert.InDelta(t, 460, pos.Image.Y, 1e-6)
+		want, _ := plane.ToFloor(openpose.Pt(300, 460))
+		assert.InDelta(t, want.X, pos.Floor.X, 1e-9)
+		assert.InDelta(t, want.Y, pos.Floor.Y, 1e-9)
+	}
+	// feet and knees hidden, extrapolated from hips near the real ankles
+	for _, part := range []openpose.CocoPart{openpose.CocoPartRAnkle, openpose.CocoPartLAnkle, openpose.CocoPartRKnee, openpose.CocoPartLKnee} {
+		delete(h.Parts, part)
+	}
+	pos, found = plane.Locate(h, 640, 480)
+	if assert.True(t, found) {
+		assert.Equal(t, ContactHips, pos.Contact)
+		assert.InDelta(t, 300, pos.Image.X, 1e-6)
+		assert.InDelta(t, 460, pos.Image.Y, 40)
+	}
+}
+
+func TestNewCameraPlane(t *testing.T) {
+	cam := calibration.Camera{
+		Width:      640,
+		Height:     480,
+		Intrinsics: calibration.Intrinsics{Fx: 400, Fy: 400, Cx: 320, Cy: 240},
+		Distortion: &calibration.Distortion{Model: calibration.BrownConrady, Coeffs: []float64{-0.25, 0.05}},
+	}
+	raw := func(floor openpose.Point) openpose.Point {
+		img, _ := camera.Project(floor)
+		return cam.Distort(img)
+	}
+	for _, floor := range []openpose.Point{openpose.Pt(0, 0), openpose.Pt(4, 0), openpose.Pt(4, 6), openpose.Pt(0, 6), openpose.Pt(1, 3)} {
+		cam.Floor = append(cam.Floor, Correspondence{Image: raw(floor), Floor: floor})
+	}
+	plane, err := NewCameraPlane(cam)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.InDelta(t, 0, plane.ReprojectionError(), 1e-6)
+	// the homography of the raw points misses the bent floor
+	distorted, err := NewPlane(cam.Floor)
+	if assert.NoError(t, err) {
+		assert.Greater(t, distorted.ReprojectionError(), 0.01)
+	}
+	want := openpose.Pt(3, 1)
+	got, ok := plane.ToFloor(raw(want))
+	assert.True(t, ok)
+	assert.InDelta(t, want.X, got.X, 1e-6)
+	assert.InDelta(t, want.Y, got.Y, 1e-6)
+	// a frame at half the calibrated resolution
+	ankle := raw(want).Mul(0.5)
+	h := posetest.Human(map[openpose.CocoPart]openpose.Point{
+		openpose.CocoPartRAnkle: ankle,
+		openpose.CocoPartLAnkle: ankle,
+	}, 320, 240)
+	pos, found := plane.Locate(h, 320, 240)
+	if assert.True(t, found) {
+		assert.InDelta(t, want.X, pos.Floor.X, 1e-6)
+		assert.InDelta(t, want.Y, pos.Floor.Y, 1e-6)
+		assert.InDelta(t, ankle.X, pos.Image.X, 1e-6)
+		assert.InDelta(t, ankle.Y, pos.Image.Y, 1e-6)
+	}
+}
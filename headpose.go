@@ -0,0 +1,179 @@
+package openpose
+
+import (
+	"math"
+)
+
+// Facing represents the direction a head faces in image
+type Facing int
+
+const (
+	// FacingUnknown not enough visible parts
+	FacingUnknown Facing = iota
+	// FacingCamera facing toward the camera
+	FacingCamera
+	// FacingLeft facing to the left of the image
+	FacingLeft
+	// FacingRight facing to the right of the image
+	FacingRight
+	// FacingAway facing away from the camera
+	FacingAway
+)
+
+// String returns Facing name
+func (f Facing) String() string {
+	switch f {
+	case FacingCamera:
+		return "camera"
+	case FacingLeft:
+		return "left"
+	case FacingRight:
+		return "right"
+	case FacingAway:
+		return "away"
+	}
+	return "unknown"
+}
+
+// eyeAngle approximate half angle between the eyes seen from the head center
+const eyeAngle = 30.0
+
+// HeadPose represents head orientation
+type HeadPose struct {
+	// Yaw in degrees, 0 faces the camera, positive values turn to the right of the image and ±180 faces away
+	Yaw float64
+	// Roll in degrees of the eye or ear line to horizontal, positive values tilt clockwise in image
+	Roll float64
+	// RollValid reports roll is computable
+	RollValid bool
+	// Facing facing direction bucketed from yaw
+	Facing Facing
+	// Confidence in [0, 1], 0 if unknown
+	Confidence float64
+}
+
+// IsFacingCamera returns the head faces the camera within maxYaw degrees
+func (p HeadPose) IsFacingCamera(maxYaw float64) bool {
+	return p.Confidence > 0 && math.Abs(p.Yaw) <= maxYaw
+}
+
+// HeadPose estimates head orientation from nose, eyes, ears and shoulders in pixel space compared to img size (w, h)
+func (h Human) HeadPose(imgW float64, imgH float64) HeadPose {
+	nose, foundNose := h.GetPart(CocoPartNose, ThresholdPartConfidence)
+	rEye, foundREye := h.GetPart(CocoPartREye, ThresholdPartConfidence)
+	lEye, foundLEye := h.GetPart(CocoPartLEye, ThresholdPartConfidence)
+	rEar, foundREar := h.GetPart(CocoPartREar, ThresholdPartConfidence)
+	lEar, foundLEar := h.GetPart(CocoPartLEar, ThresholdPartConfidence)
+	var (
+		ret  HeadPose
+		used []BodyPart
+	)
+	switch {
+	case foundNose && foundREar && foundLEar:
+		// nose offset from the ear midpoint is the sine of yaw
+		ratio := noseOffset(nose, rEar, lEar, imgW)
+		ret.Yaw = math.Asin(math.Max(-1, math.Min(1, ratio))) * 180 / math.Pi
+		used = []BodyPart{nose, rEar, lEar}
+	case foundNose && foundREye && foundLEye:
+		// eyes at ±eyeAngle on the head circle, nose offset from the eye midpoint is tan(yaw)*tan(eyeAngle/2)
+		ratio := noseOffset(nose, rEye, lEye, imgW)
+		ret.Yaw = math.Atan(ratio/math.Tan(eyeAngle/2*math.Pi/180)) * 180 / math.Pi
+		used = []BodyPart{nose, rEye, lEye}
+		if foundREar != foundLEar {
+			// one ear hidden, the head is turned at least half way to the side of the visible ear
+			sign := 1.0
+			if foundLEar {
+				sign = -1
+			}
+			ret.Yaw = sign * math.Max(math.Abs(ret.Yaw), 45)
+		}
+	case foundNose && (foundREar || foundLEar):
+		// profile, the visible ear is behind the nose
+		ear := rEar
+		if foundLEar {
+			ear = lEar
+		}
+		ret.Yaw = 90
+		if nose.Point.X < ear.Point.X {
+			ret.Yaw = -90
+		}
+		used = []BodyPart{nose, ear}
+	case foundNose && (foundREye || foundLEye):
+		// a single eye, the person shows its right side if only the right eye is visible
+		ret.Yaw = 60
+		used = []BodyPart{nose, rEye}
+		if foundLEye {
+			ret.Yaw = -60
+			used = []BodyPart{nose, lEye}
+		}
+	case !foundNose && !foundREye && !foundLEye && (foundREar || foundLEar):
+		ret.Yaw = 180
+		if foundREar {
+			used = append(used, rEar)
+		}
+		if foundLEar {
+			used = append(used, lEar)
+		}
+	}
+	confidence := 1.0
+	if len(used) == 0 {
+		// body orientation from shoulders, the right shoulder is on the left of the image facing the camera
+		rShoulder, foundR := h.GetPart(CocoPartRShoulder, ThresholdPartConfidence)
+		lShoulder, foundL := h.GetPart(CocoPartLShoulder, ThresholdPartConfidence)
+		if !foundR || !foundL {
+			return ret
+		}
+		if rShoulder.Point.X > lShoulder.Point.X {
+			ret.Yaw = 180
+		}
+		used = []BodyPart{rShoulder, lShoulder}
+		confidence = 0.5
+	}
+	var score float64
+	for _, part := range used {
+		score += float64(part.Score)
+	}
+	ret.Confidence = math.Min(1, confidence*score/float64(len(used)))
+	ret.Facing = facingFromYaw(ret.Yaw)
+	switch {
+	case foundREye && foundLEye:
+		ret.Roll, ret.RollValid = lineRoll(rEye.Point.Scale(imgW, imgH), lEye.Point.Scale(imgW, imgH))
+	case foundREar && foundLEar:
+		ret.Roll, ret.RollValid = lineRoll(rEar.Point.Scale(imgW, imgH), lEar.Point.Scale(imgW, imgH))
+	}
+	return ret
+}
+
+// noseOffset returns horizontal offset of nose to the midpoint of right and left parts, relative to half of their distance
+func noseOffset(nose BodyPart, right BodyPart, left BodyPart, imgW float64) float64 {
+	half := (left.Point.X - right.Point.X) * imgW / 2
+	if math.Abs(half) <= 1e-15 {
+		return 0
+	}
+	return (nose.Point.X - (right.Point.X+left.Point.X)/2) * imgW / half
+}
+
+// lineRoll returns the angle of the line through a and b to horizontal in degrees, ordered left to right in image
+func lineRoll(a Point, b Point) (float64, bool) {
+	if a.X > b.X {
+		a, b = b, a
+	}
+	v := b.Sub(a)
+	if v.Norm() <= 1e-15 {
+		return 0, false
+	}
+	return math.Atan2(v.Y, v.X) * 180 / math.Pi, true
+}
+
+// facingFromYaw buckets yaw in degrees into Facing
+func facingFromYaw(yaw float64) Facing {
+	switch abs := math.Abs(yaw); {
+	case abs <= 30:
+		return FacingCamera
+	case abs >= 135:
+		return FacingAway
+	case yaw > 0:
+		return FacingRight
+	}
+	return FacingLeft
+}
@@ -0,0 +1,129 @@
+package openpose
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHuman_HeadPose(t *testing.T) {
+	const imgW, imgH = 100, 100
+	// yaw of a nose offset ratio of the eyes branch
+	eyesYaw := func(ratio float64) float64 {
+		return math.Atan(ratio/math.Tan(eyeAngle/2*math.Pi/180)) * 180 / math.Pi
+	}
+	earsYaw := math.Asin(0.8) * 180 / math.Pi
+	tests := []struct {
+		name       string
+		points     map[CocoPart]Point
+		yaw        float64
+		facing     Facing
+		confidence float64
+		roll       float64
+		rollValid  bool
+	}{
+		{"ears turned right", map[CocoPart]Point{
+			CocoPartNose: Pt(58, 50), CocoPartREar: Pt(40, 50), CocoPartLEar: Pt(60, 50),
+		}, earsYaw, FacingRight, 0.9, 0, true},
+		{"ears turned left", map[CocoPart]Point{
+			CocoPartNose: Pt(42, 50), CocoPartREar: Pt(40, 50), CocoPartLEar: Pt(60, 50),
+		}, -earsYaw, FacingLeft, 0.9, 0, true},
+		{"eyes facing camera", map[CocoPart]Point{
+			CocoPartNose: Pt(50, 50), CocoPartREye: Pt(45, 40), CocoPartLEye: Pt(55, 40),
+		}, 0, FacingCamera, 0.9, 0, true},
+		{"eyes turned right", map[CocoPart]Point{
+			CocoPartNose: Pt(52, 50), CocoPartREye: Pt(45, 40), CocoPartLEye: Pt(55, 40),
+		}, eyesYaw(0.4), FacingRight, 0.9, 0, true},
+		{"eyes turned left", map[CocoPart]Point{
+			CocoPartNose: Pt(48, 50), CocoPartREye: Pt(45, 40), CocoPartLEye: Pt(55, 40),
+		}, eyesYaw(-0.4), FacingLeft, 0.9, 0, true},
+		{"eyes with right ear", map[CocoPart]Point{
+			CocoPartNose: Pt(50, 50), CocoPartREye: Pt(45, 40), CocoPartLEye: Pt(55, 40), CocoPartREar: Pt(38, 42),
+		}, 45, FacingRight, 0.9, 0, true},
+		{"eyes with left ear", map[CocoPart]Point{
+			CocoPartNose: Pt(50, 50), CocoPartREye: Pt(45, 40), CocoPartLEye: Pt(55, 40), CocoPartLEar: Pt(62, 42),
+		}, -45, FacingLeft, 0.9, 0, true},
+		{"right ear", map[CocoPart]Point{
+			CocoPartNose: Pt(40, 50), CocoPartREar: Pt(60, 45),
+		}, -90, FacingLeft, 0.9, 0, false},
+		{"left ear", map[CocoPart]Point{
+			CocoPartNose: Pt(70, 50), CocoPartLEar: Pt(50, 45),
+		}, 90, FacingRight, 0.9, 0, false},
+		{"right eye", map[CocoPart]Point{
+			CocoPartNose: Pt(50, 50), CocoPartREye: Pt(45, 40),
+		}, 60, FacingRight, 0.9, 0, false},
+		{"left eye", map[CocoPart]Point{
+			CocoPartNose: Pt(50, 50), CocoPartLEye: Pt(55, 40),
+		}, -60, FacingLeft, 0.9, 0, false},
+		{"ears only", map[CocoPart]Point{
+			CocoPartREar: Pt(40, 50), CocoPartLEar: Pt(60, 55),
+		}, 180, FacingAway, 0.9, math.Atan2(5, 20) * 180 / math.Pi, true},
+		{"right ear only", map[CocoPart]Point{
+			CocoPartREar: Pt(40, 50),
+		}, 180, FacingAway, 0.9, 0, false},
+		{"shoulders facing camera", map[CocoPart]Point{
+			CocoPartRShoulder: Pt(40, 60), CocoPartLShoulder: Pt(60, 60),
+		}, 0, FacingCamera, 0.45, 0, false},
+		{"shoulders facing away", map[CocoPart]Point{
+			CocoPartRShoulder: Pt(60, 60), CocoPartLShoulder: Pt(40, 60),
+		}, 180, FacingAway, 0.45, 0, false},
+		{"single shoulder", map[CocoPart]Point{
+			CocoPartRShoulder: Pt(40, 60), CocoPartNeck: Pt(50, 60),
+		}, 0, FacingUnknown, 0, 0, false},
+		// roll is positive tilting clockwise, the left eye lower in image
+		{"tilted eyes", map[CocoPart]Point{
+			CocoPartNose: Pt(50, 50), CocoPartREye: Pt(45, 40), CocoPartLEye: Pt(55, 45),
+		}, 0, FacingCamera, 0.9, math.Atan2(5, 10) * 180 / math.Pi, true},
+	}
+	for _, tt := range tests {
+		pose := pixelHuman(tt.points, imgW, imgH).HeadPose(imgW, imgH)
+		assert.InDelta(t, tt.yaw, pose.Yaw, 1e-9, tt.name)
+		assert.Equal(t, tt.facing, pose.Facing, tt.name)
+		assert.InDelta(t, tt.confidence, pose.Confidence, 1e-6, tt.name)
+		assert.Equal(t, tt.rollValid, pose.RollValid, tt.name)
+		assert.InDelta(t, tt.roll, pose.Roll, 1e-9, tt.name)
+	}
+}
+
+func TestLineRoll(t *testing.T) {
+	tests := []struct {
+		a, b  Point
+		want  float64
+		valid bool
+	}{
+		{Pt(0, 0), Pt(10, 0), 0, true},
+		{Pt(0, 0), Pt(10, 10), 45, true},
+		// points are ordered left to right
+		{Pt(10, 10), Pt(0, 0), 45, true},
+		{Pt(10, 0), Pt(0, 10), -45, true},
+		{Pt(5, 0), Pt(5, 10), 90, true},
+		{Pt(5, 5), Pt(5, 5), 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := lineRoll(tt.a, tt.b)
+		assert.Equal(t, tt.valid, ok, "%v %v", tt.a, tt.b)
+		assert.InDelta(t, tt.want, got, 1e-9, "%v %v", tt.a, tt.b)
+	}
+}
+
+func TestFacingFromYaw(t *testing.T) {
+	tests := []struct {
+		yaw  float64
+		want Facing
+	}{
+		{0, FacingCamera},
+		{30, FacingCamera},
+		{-30, FacingCamera},
+		{30.01, FacingRight},
+		{-30.01, FacingLeft},
+		{134.99, FacingRight},
+		{-134.99, FacingLeft},
+		{135, FacingAway},
+		{-135, FacingAway},
+		{180, FacingAway},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, facingFromYaw(tt.yaw), "yaw %v", tt.yaw)
+	}
+}
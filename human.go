@@ -35,6 +35,15 @@ func (h Human) HasPart(part CocoPart) bool {
 	return found
 }
 
+// GetPart returns body part of the human if its score is above threshold
+func (h Human) GetPart(part CocoPart, threshold float32) (BodyPart, bool) {
+	bodyPart, found := h.Parts[part]
+	if !found || bodyPart.Score <= threshold {
+		return bodyPart, false
+	}
+	return bodyPart, true
+}
+
 // GetMaxScore returns the max score of body parts
 func (h Human) GetMaxScore() float32 {
 	var score float32
@@ -46,14 +55,15 @@ func (h Human) GetMaxScore() float32 {
 	return score
 }
 
-// GetFaceBox returns upper body box compared to img size (w, h)
+// GetFaceBox returns face box compared to img size (w, h).
+// mode 0 returns a center based box, other modes return a corner based box
 func (h Human) GetFaceBox(imgW float64, imgH float64, mode int) Rectangle {
 	parts := make([]BodyPart, 0, TotalBodyParts)
 	partsMap := make(map[CocoPart]BodyPart, TotalBodyParts)
 	partPoints := make([]Point, 0, len(CoordPartsMap))
 	var (
-		x  float64
-		y  float64
+		x  = math.Inf(1)
+		y  = math.Inf(1)
 		x2 float64
 		y2 float64
 	)
@@ -151,14 +161,14 @@ func (h Human) GetFaceBox(imgW float64, imgH float64, mode int) Rectangle {
 	)
 }
 
-// GetUpperBodyBox returns upper body box compared to img size (w, h)
+// GetUpperBodyBox returns center based upper body box compared to img size (w, h)
 func (h Human) GetUpperBodyBox(imgW float64, imgH float64) Rectangle {
 	parts := make([]BodyPart, 0, TotalBodyParts)
 	partsMap := make(map[CocoPart]BodyPart, TotalBodyParts)
 	partPoints := make([]Point, 0, len(CoordPartsMap))
 	var (
-		x  float64
-		y  float64
+		x  = math.Inf(1)
+		y  = math.Inf(1)
 		x2 float64
 		y2 float64
 	)
@@ -234,3 +244,102 @@ func (h Human) GetUpperBodyBox(imgW float64, imgH float64) Rectangle {
 		RoundInt(y2-y),
 	)
 }
+
+// GetBodyBox returns full body box in pixel space compared to img size (w, h).
+// The box is padded by padding ratio of its own size on each side
+func (h Human) GetBodyBox(imgW float64, imgH float64, padding float64, threshold float32) Rectangle {
+	points := make([]Point, 0, len(h.Parts)+1)
+	for part := CocoPartNose; part < CocoPartBackground; part++ {
+		if bodyPart, found := h.GetPart(part, threshold); found {
+			points = append(points, bodyPart.Point.Scale(imgW, imgH))
+		}
+	}
+	// the nose is below the top of the head, extend by the nose to neck distance
+	nosePart, foundNose := h.GetPart(CocoPartNose, threshold)
+	neckPart, foundNeck := h.GetPart(CocoPartNeck, threshold)
+	if foundNose && foundNeck {
+		nose := nosePart.Point.Scale(imgW, imgH)
+		neck := neckPart.Point.Scale(imgW, imgH)
+		points = append(points, Pt(nose.X, nose.Y-math.Abs(neck.Y-nose.Y)*0.5))
+	}
+	return boundingBox(points, 2, imgW, imgH, padding)
+}
+
+// GetTorsoBox returns torso box in pixel space compared to img size (w, h)
+func (h Human) GetTorsoBox(imgW float64, imgH float64, padding float64, threshold float32) Rectangle {
+	return h.partsBox(TorsoParts, 3, imgW, imgH, padding, threshold)
+}
+
+// GetLowerBodyBox returns lower body box in pixel space compared to img size (w, h)
+func (h Human) GetLowerBodyBox(imgW float64, imgH float64, padding float64, threshold float32) Rectangle {
+	return h.partsBox(LowerBodyParts, 2, imgW, imgH, padding, threshold)
+}
+
+// GetHandBox returns hand box in pixel space compared to img size (w, h).
+// The hand is not detected by the model, so the box is extrapolated from elbow and wrist
+func (h Human) GetHandBox(imgW float64, imgH float64, side Side, padding float64, threshold float32) Rectangle {
+	elbow, wrist := CocoPartRElbow, CocoPartRWrist
+	if side == SideLeft {
+		elbow, wrist = CocoPartLElbow, CocoPartLWrist
+	}
+	elbowPart, foundElbow := h.GetPart(elbow, threshold)
+	wristPart, foundWrist := h.GetPart(wrist, threshold)
+	if !foundElbow || !foundWrist {
+		return ZR
+	}
+	elbowPoint := elbowPart.Point.Scale(imgW, imgH)
+	wristPoint := wristPart.Point.Scale(imgW, imgH)
+	forearm := wristPoint.Sub(elbowPoint)
+	// hand center lies beyond the wrist along the forearm, hand length is about half of the forearm
+	center := wristPoint.Add(forearm.Mul(0.3))
+	halfSize := forearm.Norm() * 0.4
+	if halfSize <= 1e-15 {
+		return ZR
+	}
+	points := []Point{
+		Pt(center.X-halfSize, center.Y-halfSize),
+		Pt(center.X+halfSize, center.Y+halfSize),
+	}
+	return boundingBox(points, 2, imgW, imgH, padding)
+}
+
+// partsBox returns bounding box of given parts, minPoints is the minimum count of visible parts required
+func (h Human) partsBox(parts []CocoPart, minPoints int, imgW float64, imgH float64, padding float64, threshold float32) Rectangle {
+	points := make([]Point, 0, len(parts))
+	for _, part := range parts {
+		if bodyPart, found := h.GetPart(part, threshold); found {
+			points = append(points, bodyPart.Point.Scale(imgW, imgH))
+		}
+	}
+	return boundingBox(points, minPoints, imgW, imgH, padding)
+}
+
+// boundingBox returns corner based box of points padded by padding ratio and fit into the image frame
+func boundingBox(points []Point, minPoints int, imgW float64, imgH float64, padding float64) Rectangle {
+	if len(points) == 0 || len(points) < minPoints {
+		return ZR
+	}
+	x, y := points[0].X, points[0].Y
+	x2, y2 := x, y
+	for _, point := range points[1:] {
+		x = math.Min(x, point.X)
+		y = math.Min(y, point.Y)
+		x2 = math.Max(x2, point.X)
+		y2 = math.Max(y2, point.Y)
+	}
+	dx := (x2 - x) * padding
+	dy := (y2 - y) * padding
+	x = math.Max(0, x-dx)
+	y = math.Max(0, y-dy)
+	x2 = math.Min(imgW, x2+dx)
+	y2 = math.Min(imgH, y2+dy)
+	if RoundInt(x2-x) <= 0 || RoundInt(y2-y) <= 0 {
+		return ZR
+	}
+	return Rect(
+		RoundInt(x),
+		RoundInt(y),
+		RoundInt(x2-x),
+		RoundInt(y2-y),
+	)
+}
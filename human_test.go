@@ -0,0 +1,131 @@
+package openpose
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pixelHuman returns a human of points in pixels of an image of size (w, h), parts are scored 0.9
+func pixelHuman(points map[CocoPart]Point, w float64, h float64) Human {
+	ret := NewHuman()
+	for part, point := range points {
+		ret.Parts[part] = NewBodyPart(part, Pt(point.X/w, point.Y/h), 0.9)
+	}
+	return *ret
+}
+
+func TestHuman_Boxes(t *testing.T) {
+	const imgW, imgH = 200, 100
+	torso := map[CocoPart]Point{
+		CocoPartNeck: Pt(100, 20), CocoPartRShoulder: Pt(80, 22), CocoPartLShoulder: Pt(120, 22),
+		CocoPartRHip: Pt(90, 60), CocoPartLHip: Pt(110, 60),
+	}
+	// a left shoulder scored below the threshold is not part of the box
+	lowShoulder := pixelHuman(torso, imgW, imgH)
+	lowShoulder.Parts[CocoPartLShoulder] = NewBodyPart(CocoPartLShoulder, Pt(120.0/imgW, 22.0/imgH), 0.2)
+	tests := []struct {
+		name string
+		box  func() Rectangle
+		want Rectangle
+	}{
+		{"torso", func() Rectangle {
+			return pixelHuman(torso, imgW, imgH).GetTorsoBox(imgW, imgH, 0, ThresholdPartConfidence)
+		}, Rect(80, 20, 40, 40)},
+		{"torso padded", func() Rectangle {
+			return pixelHuman(torso, imgW, imgH).GetTorsoBox(imgW, imgH, 0.1, ThresholdPartConfidence)
+		}, Rect(76, 16, 48, 48)},
+		{"torso threshold", func() Rectangle {
+			return lowShoulder.GetTorsoBox(imgW, imgH, 0, ThresholdPartConfidence)
+		}, Rect(80, 20, 30, 40)},
+		{"torso below threshold of all parts", func() Rectangle {
+			return pixelHuman(torso, imgW, imgH).GetTorsoBox(imgW, imgH, 0, 0.95)
+		}, ZR},
+		{"torso min points", func() Rectangle {
+			return pixelHuman(map[CocoPart]Point{
+				CocoPartNeck: Pt(100, 20), CocoPartRShoulder: Pt(80, 22),
+			}, imgW, imgH).GetTorsoBox(imgW, imgH, 0, ThresholdPartConfidence)
+		}, ZR},
+		{"lower body clipped to the image", func() Rectangle {
+			return pixelHuman(map[CocoPart]Point{
+				CocoPartRHip: Pt(20, 50), CocoPartRKnee: Pt(15, 75), CocoPartRAnkle: Pt(10, 95),
+			}, imgW, imgH).GetLowerBodyBox(imgW, imgH, 0.2, ThresholdPartConfidence)
+		}, Rect(8, 41, 14, 59)},
+		{"lower body min points", func() Rectangle {
+			return pixelHuman(map[CocoPart]Point{
+				CocoPartRHip: Pt(20, 50),
+			}, imgW, imgH).GetLowerBodyBox(imgW, imgH, 0, ThresholdPartConfidence)
+		}, ZR},
+		// the top of the head is extended above the nose by half of the nose to neck distance
+		{"body", func() Rectangle {
+			return pixelHuman(map[CocoPart]Point{
+				CocoPartNose: Pt(100, 20), CocoPartNeck: Pt(100, 30),
+				CocoPartRAnkle: Pt(90, 90), CocoPartLAnkle: Pt(110, 90),
+			}, imgW, imgH).GetBodyBox(imgW, imgH, 0, ThresholdPartConfidence)
+		}, Rect(90, 15, 20, 75)},
+		{"body min points", func() Rectangle {
+			return pixelHuman(map[CocoPart]Point{
+				CocoPartNose: Pt(100, 20),
+			}, imgW, imgH).GetBodyBox(imgW, imgH, 0, ThresholdPartConfidence)
+		}, ZR},
+		// the hand is centered 0.3 forearm beyond the wrist, 0.8 forearm in size
+		{"right hand", func() Rectangle {
+			return pixelHuman(map[CocoPart]Point{
+				CocoPartRElbow: Pt(100, 50), CocoPartRWrist: Pt(100, 70),
+			}, imgW, imgH).GetHandBox(imgW, imgH, SideRight, 0, ThresholdPartConfidence)
+		}, Rect(92, 68, 16, 16)},
+		{"left hand", func() Rectangle {
+			return pixelHuman(map[CocoPart]Point{
+				CocoPartLElbow: Pt(50, 50), CocoPartLWrist: Pt(70, 50),
+			}, imgW, imgH).GetHandBox(imgW, imgH, SideLeft, 0, ThresholdPartConfidence)
+		}, Rect(68, 42, 16, 16)},
+		{"hand clipped to the image", func() Rectangle {
+			return pixelHuman(map[CocoPart]Point{
+				CocoPartRElbow: Pt(100, 70), CocoPartRWrist: Pt(100, 90),
+			}, imgW, imgH).GetHandBox(imgW, imgH, SideRight, 0, ThresholdPartConfidence)
+		}, Rect(92, 88, 16, 12)},
+		{"hand of the other side", func() Rectangle {
+			return pixelHuman(map[CocoPart]Point{
+				CocoPartRElbow: Pt(100, 50), CocoPartRWrist: Pt(100, 70),
+			}, imgW, imgH).GetHandBox(imgW, imgH, SideLeft, 0, ThresholdPartConfidence)
+		}, ZR},
+		// boxes of humans away from the image origin are not stretched to it
+		{"upper body", func() Rectangle {
+			return pixelHuman(map[CocoPart]Point{
+				CocoPartNose: Pt(100, 20), CocoPartNeck: Pt(100, 30),
+				CocoPartRShoulder: Pt(85, 30), CocoPartLShoulder: Pt(115, 30),
+				CocoPartRHip: Pt(90, 46), CocoPartLHip: Pt(110, 46),
+			}, imgW, imgH).GetUpperBodyBox(imgW, imgH)
+		}, Rect(100, 29, 39, 34)},
+		{"face", func() Rectangle {
+			return pixelHuman(map[CocoPart]Point{
+				CocoPartNose: Pt(100, 40), CocoPartNeck: Pt(100, 60),
+				CocoPartREye: Pt(95, 36), CocoPartLEye: Pt(105, 36),
+				CocoPartREar: Pt(90, 38), CocoPartLEar: Pt(110, 38),
+			}, imgW, imgH).GetFaceBox(imgW, imgH, 1)
+		}, Rect(90, 28, 20, 20)},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.box(), tt.name)
+	}
+}
+
+func TestBoundingBox(t *testing.T) {
+	tests := []struct {
+		name      string
+		points    []Point
+		minPoints int
+		padding   float64
+		want      Rectangle
+	}{
+		{"empty", nil, 0, 0, ZR},
+		{"min points", []Point{Pt(10, 10), Pt(20, 20)}, 3, 0, ZR},
+		{"single point", []Point{Pt(10, 10)}, 1, 0.5, ZR},
+		{"box", []Point{Pt(20, 10), Pt(10, 30), Pt(15, 20)}, 3, 0, Rect(10, 10, 10, 20)},
+		{"padded", []Point{Pt(20, 10), Pt(10, 30)}, 2, 0.5, Rect(5, 0, 20, 40)},
+		{"clipped", []Point{Pt(-10, 40), Pt(90, 60)}, 2, 0.25, Rect(0, 35, 100, 15)},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, boundingBox(tt.points, tt.minPoints, 100, 50, tt.padding), tt.name)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"image"
 	"image/color"
+	"image/draw"
 	"image/jpeg"
 	"math"
 
@@ -17,8 +18,8 @@ import (
 
 // ImagePreprocess preprocess image for model
 func ImagePreprocess(img image.Image, modelSize ModelSize, sharpenSigma float64) (image.Image, Size) {
-	imgW := float64(img.Bounds().Max.X)
-	imgH := float64(img.Bounds().Max.Y)
+	imgW := float64(img.Bounds().Dx())
+	imgH := float64(img.Bounds().Dy())
 	targetW := float64(modelSize[0])
 	targetH := float64(modelSize[1])
 	out := resizeImage(img, imgW, imgH, targetW, targetH)
@@ -43,15 +44,14 @@ func resizeImage(img image.Image, sourceW float64, sourceH float64, targetW floa
 }
 
 func padImage(img image.Image, targetW float64, targetH float64) (image.Image, Size) {
-	imgW := float64(img.Bounds().Max.X)
-	imgH := float64(img.Bounds().Max.Y)
+	imgW := float64(img.Bounds().Dx())
+	imgH := float64(img.Bounds().Dy())
 	normPadding := Size{
 		W: imgW / targetW,
 		H: imgH / targetH,
 	}
 	out := image.NewRGBA(image.Rect(0, 0, int(targetW), int(targetH)))
-	gc := draw2dimg.NewGraphicContext(out)
-	gc.DrawImage(img)
+	draw.Draw(out, img.Bounds().Sub(img.Bounds().Min), img, img.Bounds().Min, draw.Src)
 	return out, normPadding
 }
 
@@ -64,8 +64,8 @@ func makeTensorFromImage(img image.Image) (*tf.Tensor, error) {
 	if err != nil {
 		return nil, err
 	}
-	w := img.Bounds().Max.X
-	h := img.Bounds().Max.Y
+	w := img.Bounds().Dx()
+	h := img.Bounds().Dy()
 	graph, input, output, err := makeTransformImageGraph(int32(w), int32(h))
 	if err != nil {
 		return nil, err
@@ -156,12 +156,14 @@ func meanStd(img [][][]float32) (mean float32, std float32) {
 }
 
 func DrawHumans(img image.Image, humans []Human, strokeWidth float64) image.Image {
-	imgW := float64(img.Bounds().Max.X)
-	imgH := float64(img.Bounds().Max.Y)
+	bounds := img.Bounds()
+	imgW := float64(bounds.Dx())
+	imgH := float64(bounds.Dy())
 	//log.Printf("w:%f, h:%f\n", imgW, imgH)
-	out := image.NewRGBA(img.Bounds())
+	// draw2d rasterizes from origin, draw at origin and move to img bounds at last
+	out := image.NewRGBA(bounds.Sub(bounds.Min))
+	draw.Draw(out, out.Bounds(), img, bounds.Min, draw.Src)
 	gc := draw2dimg.NewGraphicContext(out)
-	gc.DrawImage(img)
 	startPart := CocoPartNose
 	maxPart := CocoPartLEar
 	for _, human := range humans {
@@ -204,5 +206,6 @@ func DrawHumans(img image.Image, humans []Human, strokeWidth float64) image.Imag
 			gc.FillStroke()
 		}
 	}
+	out.Rect = bounds
 	return out
 }
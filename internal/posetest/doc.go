@@ -0,0 +1,2 @@
+// Package posetest builds humans of pixel points for tests
+package posetest
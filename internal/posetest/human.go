@@ -0,0 +1,17 @@
+package posetest
+
+import (
+	"github.com/bububa/openpose"
+)
+
+// Score score of parts of built humans
+const Score float32 = 0.9
+
+// Human returns a human of points in pixels of an image of size (w, h), parts are scored Score
+func Human(points map[openpose.CocoPart]openpose.Point, w float64, h float64) openpose.Human {
+	ret := openpose.NewHuman()
+	for part, point := range points {
+		ret.Parts[part] = openpose.NewBodyPart(part, openpose.Pt(point.X/w, point.Y/h), Score)
+	}
+	return *ret
+}
@@ -0,0 +1,2 @@
+// Package interpolate fills missing body parts of tracked humans
+package interpolate
@@ -0,0 +1,161 @@
+package interpolate
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/bububa/openpose"
+)
+
+// Config represents online gap filler config
+type Config struct {
+	// MaxGap max duration a missing part is extrapolated from its last detections
+	MaxGap time.Duration
+	// Symmetric enables estimating parts still missing from the opposite limb
+	Symmetric bool
+}
+
+// DefaultConfig returns default gap filler config
+func DefaultConfig() Config {
+	return Config{
+		MaxGap:    300 * time.Millisecond,
+		Symmetric: true,
+	}
+}
+
+// symmetricParents represents the part each symmetric estimate starts from, parents come first
+var symmetricParents = []struct {
+	Part   openpose.CocoPart
+	Parent openpose.CocoPart
+}{
+	{openpose.CocoPartRShoulder, openpose.CocoPartNeck},
+	{openpose.CocoPartLShoulder, openpose.CocoPartNeck},
+	{openpose.CocoPartRHip, openpose.CocoPartNeck},
+	{openpose.CocoPartLHip, openpose.CocoPartNeck},
+	{openpose.CocoPartRElbow, openpose.CocoPartRShoulder},
+	{openpose.CocoPartLElbow, openpose.CocoPartLShoulder},
+	{openpose.CocoPartRWrist, openpose.CocoPartRElbow},
+	{openpose.CocoPartLWrist, openpose.CocoPartLElbow},
+	{openpose.CocoPartRKnee, openpose.CocoPartRHip},
+	{openpose.CocoPartLKnee, openpose.CocoPartLHip},
+	{openpose.CocoPartRAnkle, openpose.CocoPartRKnee},
+	{openpose.CocoPartLAnkle, openpose.CocoPartLKnee},
+	{openpose.CocoPartREye, openpose.CocoPartNose},
+	{openpose.CocoPartLEye, openpose.CocoPartNose},
+	{openpose.CocoPartREar, openpose.CocoPartREye},
+	{openpose.CocoPartLEar, openpose.CocoPartLEye},
+}
+
+// observation represents the last two detections of a part in pixels
+type observation struct {
+	last     openpose.BodyPart
+	lastTime time.Time
+	prev     openpose.Point
+	prevTime time.Time
+	hasPrev  bool
+}
+
+// Filler fills missing parts of tracked humans frame by frame
+type Filler struct {
+	cfg          Config
+	observations map[int]map[openpose.CocoPart]*observation
+	mutex        sync.Mutex
+}
+
+// NewFiller returns a new Filler
+func NewFiller(cfg Config) *Filler {
+	return &Filler{
+		cfg:          cfg,
+		observations: make(map[int]map[openpose.CocoPart]*observation),
+	}
+}
+
+// Fill returns human of track at t with missing parts extrapolated from the last detections of the track,
+// and then estimated from the opposite limb. Points are computed in pixel space compared to img size (w, h)
+func (f *Filler) Fill(trackID int, h openpose.Human, imgW float64, imgH float64, t time.Time) openpose.Human {
+	if imgW <= 0 || imgH <= 0 {
+		return h
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	observations, found := f.observations[trackID]
+	if !found {
+		observations = make(map[openpose.CocoPart]*observation, openpose.TotalBodyParts)
+		f.observations[trackID] = observations
+	}
+	ret := cloneHuman(h)
+	for part := openpose.CocoPart(0); part < openpose.CocoPart(openpose.TotalBodyParts); part++ {
+		if bodyPart, found := detected(h, part); found {
+			point := bodyPart.Point.Scale(imgW, imgH)
+			bodyPart.Point = point
+			if obs, found := observations[part]; found && t.Sub(obs.lastTime) <= f.cfg.MaxGap && t.After(obs.lastTime) {
+				obs.prev, obs.prevTime, obs.hasPrev = obs.last.Point, obs.lastTime, true
+				obs.last, obs.lastTime = bodyPart, t
+			} else {
+				observations[part] = &observation{last: bodyPart, lastTime: t}
+			}
+			continue
+		}
+		obs, found := observations[part]
+		if !found || t.Sub(obs.lastTime) > f.cfg.MaxGap {
+			continue
+		}
+		point := obs.last.Point
+		if dt := obs.lastTime.Sub(obs.prevTime).Seconds(); obs.hasPrev && dt > 0 {
+			velocity := obs.last.Point.Sub(obs.prev).Mul(1 / dt)
+			point = point.Add(velocity.Mul(t.Sub(obs.lastTime).Seconds()))
+		}
+		ret.Parts[part] = openpose.BodyPart{
+			Part:        part,
+			Point:       openpose.Pt(point.X/imgW, point.Y/imgH),
+			Score:       obs.last.Score,
+			Synthesized: true,
+		}
+	}
+	if f.cfg.Symmetric {
+		fillSymmetric(&ret, imgW, imgH)
+	}
+	return ret
+}
+
+// Forget drops observations of track
+func (f *Filler) Forget(trackID int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	delete(f.observations, trackID)
+}
+
+// fillSymmetric estimates missing parts by reflecting the opposite limb across the body axis
+func fillSymmetric(h *openpose.Human, imgW float64, imgH float64) {
+	axis := openpose.Pt(0, 1)
+	neck, neckFound := h.PixelCenter([]openpose.CocoPart{openpose.CocoPartNeck}, imgW, imgH)
+	hips, hipsFound := h.PixelCenter([]openpose.CocoPart{openpose.CocoPartRHip, openpose.CocoPartLHip}, imgW, imgH)
+	if neckFound && hipsFound {
+		if v := hips.Sub(neck); v.Norm() > 0 {
+			axis = v.Mul(1 / v.Norm())
+		}
+	}
+	for _, pair := range symmetricParents {
+		if _, found := h.GetPart(pair.Part, openpose.ThresholdPartConfidence); found {
+			continue
+		}
+		mirror := pair.Part.Mirror()
+		mirrorParent := pair.Parent.Mirror()
+		parent, parentFound := h.GetPart(pair.Parent, openpose.ThresholdPartConfidence)
+		source, sourceFound := h.GetPart(mirror, openpose.ThresholdPartConfidence)
+		sourceParent, sourceParentFound := h.GetPart(mirrorParent, openpose.ThresholdPartConfidence)
+		if !parentFound || !sourceFound || !sourceParentFound {
+			continue
+		}
+		v := source.Point.Scale(imgW, imgH).Sub(sourceParent.Point.Scale(imgW, imgH))
+		reflected := axis.Mul(2 * v.Dot(axis)).Sub(v)
+		point := parent.Point.Scale(imgW, imgH).Add(reflected)
+		h.Parts[pair.Part] = openpose.BodyPart{
+			Part:        pair.Part,
+			Point:       openpose.Pt(point.X/imgW, point.Y/imgH),
+			Score:       float32(math.Min(float64(parent.Score), math.Min(float64(source.Score), float64(sourceParent.Score)))),
+			Synthesized: true,
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package interpolate
+
+import (
+	"time"
+
+	"github.com/bububa/openpose"
+)
+
+// Method represents interpolation method in time
+type Method int
+
+const (
+	// Linear interpolates linearly between the detections around a gap
+	Linear Method = iota
+	// CatmullRom interpolates by Catmull-Rom spline through the detections around a gap
+	CatmullRom
+)
+
+// Sample represents a human of a track at time
+type Sample struct {
+	// Human human of the sample
+	Human openpose.Human
+	// Time time of the sample
+	Time time.Time
+}
+
+// detected returns part of human if it is detected with score above ThresholdPartConfidence
+func detected(h openpose.Human, part openpose.CocoPart) (openpose.BodyPart, bool) {
+	bodyPart, found := h.GetPart(part, openpose.ThresholdPartConfidence)
+	if !found || bodyPart.Synthesized {
+		return bodyPart, false
+	}
+	return bodyPart, true
+}
+
+// cloneHuman returns a copy of human which parts could be modified
+func cloneHuman(h openpose.Human) openpose.Human {
+	ret := openpose.Human{
+		Parts: make(map[openpose.CocoPart]openpose.BodyPart, openpose.TotalBodyParts),
+		Score: h.Score,
+	}
+	for part, bodyPart := range h.Parts {
+		ret.Parts[part] = bodyPart
+	}
+	return ret
+}
+
+// FillGaps returns samples of a track ordered by time with missing parts interpolated from detections around the gap.
+// Gaps longer than maxGap between the detections are left missing
+func FillGaps(samples []Sample, maxGap time.Duration, method Method) []Sample {
+	ret := make([]Sample, len(samples))
+	for idx, sample := range samples {
+		ret[idx] = Sample{Human: cloneHuman(sample.Human), Time: sample.Time}
+	}
+	for part := openpose.CocoPart(0); part < openpose.CocoPart(openpose.TotalBodyParts); part++ {
+		var keys []int
+		for idx, sample := range samples {
+			if _, found := detected(sample.Human, part); found {
+				keys = append(keys, idx)
+			}
+		}
+		for k := 0; k+1 < len(keys); k++ {
+			from, to := keys[k], keys[k+1]
+			if to-from < 2 || samples[to].Time.Sub(samples[from].Time) > maxGap {
+				continue
+			}
+			prev, next := from, to
+			if k > 0 {
+				prev = keys[k-1]
+			}
+			if k+2 < len(keys) {
+				next = keys[k+2]
+			}
+			for idx := from + 1; idx < to; idx++ {
+				ret[idx].Human.Parts[part] = interpolate(samples, part, [4]int{prev, from, to, next}, samples[idx].Time, method)
+			}
+		}
+	}
+	return ret
+}
+
+// interpolate returns part at t between keys[1] and keys[2], keys[0] and keys[3] are neighbour detections for spline tangents
+func interpolate(samples []Sample, part openpose.CocoPart, keys [4]int, t time.Time, method Method) openpose.BodyPart {
+	a, _ := detected(samples[keys[1]].Human, part)
+	b, _ := detected(samples[keys[2]].Human, part)
+	span := samples[keys[2]].Time.Sub(samples[keys[1]].Time).Seconds()
+	u := 0.5
+	if span > 0 {
+		u = t.Sub(samples[keys[1]].Time).Seconds() / span
+	}
+	point := a.Point.Mul(1 - u).Add(b.Point.Mul(u))
+	if method == CatmullRom && span > 0 {
+		m0 := tangent(samples, part, keys[0], keys[2]).Mul(span)
+		m1 := tangent(samples, part, keys[1], keys[3]).Mul(span)
+		u2, u3 := u*u, u*u*u
+		point = a.Point.Mul(2*u3 - 3*u2 + 1).
+			Add(m0.Mul(u3 - 2*u2 + u)).
+			Add(b.Point.Mul(-2*u3 + 3*u2)).
+			Add(m1.Mul(u3 - u2))
+	}
+	return openpose.BodyPart{
+		Part:        part,
+		Point:       point,
+		Score:       a.Score*float32(1-u) + b.Score*float32(u),
+		Synthesized: true,
+	}
+}
+
+// tangent returns velocity of part between samples from and to per second
+func tangent(samples []Sample, part openpose.CocoPart, from int, to int) openpose.Point {
+	dt := samples[to].Time.Sub(samples[from].Time).Seconds()
+	if dt <= 0 {
+		return openpose.ZP
+	}
+	a, _ := detected(samples[from].Human, part)
+	b, _ := detected(samples[to].Human, part)
+	return b.Point.Sub(a.Point).Mul(1 / dt)
+}
@@ -0,0 +1,79 @@
+package interpolate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/internal/posetest"
+)
+
+// newHuman returns a standing human with arms down in a 640x480 image
+func newHuman() openpose.Human {
+	points := map[openpose.CocoPart]openpose.Point{
+		openpose.CocoPartNose:      openpose.Pt(320, 40),
+		openpose.CocoPartNeck:      openpose.Pt(320, 80),
+		openpose.CocoPartRShoulder: openpose.Pt(290, 80),
+		openpose.CocoPartLShoulder: openpose.Pt(350, 80),
+		openpose.CocoPartRElbow:    openpose.Pt(280, 140),
+		openpose.CocoPartLElbow:    openpose.Pt(360, 140),
+		openpose.CocoPartRWrist:    openpose.Pt(270, 200),
+		openpose.CocoPartLWrist:    openpose.Pt(370, 200),
+		openpose.CocoPartRHip:      openpose.Pt(300, 200),
+		openpose.CocoPartLHip:      openpose.Pt(340, 200),
+	}
+	return posetest.Human(points, 640, 480)
+}
+
+func TestFillGaps(t *testing.T) {
+	start := time.Now()
+	samples := make([]Sample, 5)
+	for idx := range samples {
+		h := newHuman()
+		h.Parts[openpose.CocoPartRWrist] = openpose.NewBodyPart(openpose.CocoPartRWrist, openpose.Pt(float64(idx)*0.1, 0.5), 0.9)
+		samples[idx] = Sample{Human: h, Time: start.Add(time.Duration(idx) * 100 * time.Millisecond)}
+	}
+	delete(samples[1].Human.Parts, openpose.CocoPartRWrist)
+	delete(samples[2].Human.Parts, openpose.CocoPartRWrist)
+	for _, method := range []Method{Linear, CatmullRom} {
+		filled := FillGaps(samples, 500*time.Millisecond, method)
+		for idx := 1; idx <= 2; idx++ {
+			part := filled[idx].Human.Parts[openpose.CocoPartRWrist]
+			assert.True(t, part.Synthesized)
+			assert.InDelta(t, float64(idx)*0.1, part.Point.X, 1e-9)
+			assert.InDelta(t, 0.5, part.Point.Y, 1e-9)
+		}
+		assert.False(t, filled[0].Human.Parts[openpose.CocoPartRWrist].Synthesized)
+		// input is left untouched
+		assert.False(t, samples[1].Human.HasPart(openpose.CocoPartRWrist))
+		// gap too long
+		filled = FillGaps(samples, 200*time.Millisecond, method)
+		assert.False(t, filled[1].Human.HasPart(openpose.CocoPartRWrist))
+	}
+}
+
+func TestFiller(t *testing.T) {
+	start := time.Now()
+	f := NewFiller(DefaultConfig())
+	for idx := 0; idx < 3; idx++ {
+		h := newHuman()
+		h.Parts[openpose.CocoPartRWrist] = openpose.NewBodyPart(openpose.CocoPartRWrist, openpose.Pt((270+float64(idx)*10)/640, 200.0/480), 0.9)
+		f.Fill(1, h, 640, 480, start.Add(time.Duration(idx)*100*time.Millisecond))
+	}
+	// extrapolated from the last detections
+	h := newHuman()
+	delete(h.Parts, openpose.CocoPartRWrist)
+	out := f.Fill(1, h, 640, 480, start.Add(300*time.Millisecond))
+	part := out.Parts[openpose.CocoPartRWrist]
+	assert.True(t, part.Synthesized)
+	assert.InDelta(t, 300, part.Point.X*640, 1e-6)
+	// estimated from the opposite limb once the gap is too long
+	out = f.Fill(1, h, 640, 480, start.Add(time.Second))
+	part = out.Parts[openpose.CocoPartRWrist]
+	assert.True(t, part.Synthesized)
+	assert.InDelta(t, 270, part.Point.X*640, 1e-6)
+	assert.InDelta(t, 200, part.Point.Y*480, 1e-6)
+	assert.False(t, out.Parts[openpose.CocoPartLWrist].Synthesized)
+}
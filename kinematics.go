@@ -0,0 +1,195 @@
+package openpose
+
+import (
+	"math"
+)
+
+// Measure represents a kinematic value and whether it is computable with the visible parts
+type Measure struct {
+	// Value of measure, degrees for angles and pixels for lengths
+	Value float64
+	// Valid reports the value is computable
+	Valid bool
+}
+
+// Limb represents a body segment between two parts
+type Limb [2]CocoPart
+
+var (
+	// LimbRUpperArm right shoulder to right elbow
+	LimbRUpperArm = Limb{CocoPartRShoulder, CocoPartRElbow}
+	// LimbRForearm right elbow to right wrist
+	LimbRForearm = Limb{CocoPartRElbow, CocoPartRWrist}
+	// LimbLUpperArm left shoulder to left elbow
+	LimbLUpperArm = Limb{CocoPartLShoulder, CocoPartLElbow}
+	// LimbLForearm left elbow to left wrist
+	LimbLForearm = Limb{CocoPartLElbow, CocoPartLWrist}
+	// LimbRThigh right hip to right knee
+	LimbRThigh = Limb{CocoPartRHip, CocoPartRKnee}
+	// LimbRShin right knee to right ankle
+	LimbRShin = Limb{CocoPartRKnee, CocoPartRAnkle}
+	// LimbLThigh left hip to left knee
+	LimbLThigh = Limb{CocoPartLHip, CocoPartLKnee}
+	// LimbLShin left knee to left ankle
+	LimbLShin = Limb{CocoPartLKnee, CocoPartLAnkle}
+	// LimbShoulders right shoulder to left shoulder
+	LimbShoulders = Limb{CocoPartRShoulder, CocoPartLShoulder}
+	// LimbHips right hip to left hip
+	LimbHips = Limb{CocoPartRHip, CocoPartLHip}
+)
+
+// Limbs represents limbs measured by Kinematics
+var Limbs = []Limb{
+	LimbRUpperArm,
+	LimbRForearm,
+	LimbLUpperArm,
+	LimbLForearm,
+	LimbRThigh,
+	LimbRShin,
+	LimbLThigh,
+	LimbLShin,
+	LimbShoulders,
+	LimbHips,
+}
+
+// JointAngleParts represents the two neighbour parts forming the angle at a joint
+var JointAngleParts = map[CocoPart][2]CocoPart{
+	CocoPartRElbow:    {CocoPartRShoulder, CocoPartRWrist},
+	CocoPartLElbow:    {CocoPartLShoulder, CocoPartLWrist},
+	CocoPartRShoulder: {CocoPartRHip, CocoPartRElbow},
+	CocoPartLShoulder: {CocoPartLHip, CocoPartLElbow},
+	CocoPartRHip:      {CocoPartRShoulder, CocoPartRKnee},
+	CocoPartLHip:      {CocoPartLShoulder, CocoPartLKnee},
+	CocoPartRKnee:     {CocoPartRHip, CocoPartRAnkle},
+	CocoPartLKnee:     {CocoPartLHip, CocoPartLAnkle},
+}
+
+// Kinematics represents joint angles, trunk inclination and limb lengths of a human
+type Kinematics struct {
+	// Angles joint angles in degrees keyed by joint, 180 means fully extended
+	Angles map[CocoPart]Measure
+	// TrunkInclination trunk angle to vertical in degrees, 0 means upright and 90 means horizontal
+	TrunkInclination Measure
+	// TorsoLength neck to mid hip length in pixels, of the visible hip if only one is
+	TorsoLength Measure
+	// Lengths limb lengths in pixels
+	Lengths map[Limb]Measure
+}
+
+// Ratio returns length ratio of limb a to limb b
+func (k Kinematics) Ratio(a Limb, b Limb) Measure {
+	la, lb := k.Lengths[a], k.Lengths[b]
+	if !la.Valid || !lb.Valid || lb.Value <= 1e-15 {
+		return Measure{}
+	}
+	return Measure{Value: la.Value / lb.Value, Valid: true}
+}
+
+// Kinematics returns kinematics of human in pixel space compared to img size (w, h)
+func (h Human) Kinematics(imgW float64, imgH float64) Kinematics {
+	k := Kinematics{
+		Angles:  make(map[CocoPart]Measure, len(JointAngleParts)),
+		Lengths: make(map[Limb]Measure, len(Limbs)),
+	}
+	for joint := range JointAngleParts {
+		k.Angles[joint] = toMeasure(h.JointAngle(joint, imgW, imgH))
+	}
+	for _, limb := range Limbs {
+		k.Lengths[limb] = toMeasure(h.LimbLength(limb, imgW, imgH))
+	}
+	k.TrunkInclination = toMeasure(h.TrunkInclination(imgW, imgH))
+	k.TorsoLength = toMeasure(h.TorsoLength(imgW, imgH))
+	return k
+}
+
+// JointAngle returns the angle at joint in degrees, false if the joint is not supported or parts are missing
+func (h Human) JointAngle(joint CocoPart, imgW float64, imgH float64) (float64, bool) {
+	neighbours, found := JointAngleParts[joint]
+	if !found {
+		return 0, false
+	}
+	center, foundCenter := h.pixelPoint(joint, imgW, imgH)
+	a, foundA := h.pixelPoint(neighbours[0], imgW, imgH)
+	b, foundB := h.pixelPoint(neighbours[1], imgW, imgH)
+	if !foundCenter || !foundA || !foundB {
+		return 0, false
+	}
+	return VectorAngle(a.Sub(center), b.Sub(center))
+}
+
+// TrunkInclination returns the angle between mid hip to neck vector and vertical in degrees.
+// The neck falls back to the center of visible shoulders. A single visible hip is taken as the mid hip,
+// hips overlap in side views where the other one is often occluded
+func (h Human) TrunkInclination(imgW float64, imgH float64) (float64, bool) {
+	top, foundTop := h.pixelPoint(CocoPartNeck, imgW, imgH)
+	if !foundTop {
+		top, foundTop = h.PixelCenter([]CocoPart{CocoPartRShoulder, CocoPartLShoulder}, imgW, imgH)
+	}
+	hip, foundHip := h.PixelCenter([]CocoPart{CocoPartRHip, CocoPartLHip}, imgW, imgH)
+	if !foundTop || !foundHip {
+		return 0, false
+	}
+	// image y axis points down, so up is (0, -1)
+	return VectorAngle(top.Sub(hip), Pt(0, -1))
+}
+
+// TorsoLength returns neck to mid hip length in pixels, a single visible hip is taken as the mid hip
+func (h Human) TorsoLength(imgW float64, imgH float64) (float64, bool) {
+	neck, foundNeck := h.pixelPoint(CocoPartNeck, imgW, imgH)
+	hip, foundHip := h.PixelCenter([]CocoPart{CocoPartRHip, CocoPartLHip}, imgW, imgH)
+	if !foundNeck || !foundHip {
+		return 0, false
+	}
+	return neck.Distance(hip), true
+}
+
+// LimbLength returns limb length in pixels
+func (h Human) LimbLength(limb Limb, imgW float64, imgH float64) (float64, bool) {
+	a, foundA := h.pixelPoint(limb[0], imgW, imgH)
+	b, foundB := h.pixelPoint(limb[1], imgW, imgH)
+	if !foundA || !foundB {
+		return 0, false
+	}
+	return a.Distance(b), true
+}
+
+// PixelCenter returns the center of visible parts in pixel space, false if none of them is visible
+func (h Human) PixelCenter(parts []CocoPart, imgW float64, imgH float64) (Point, bool) {
+	var (
+		center Point
+		count  float64
+	)
+	for _, part := range parts {
+		if point, found := h.pixelPoint(part, imgW, imgH); found {
+			center = center.Add(point)
+			count++
+		}
+	}
+	if count == 0 {
+		return ZP, false
+	}
+	return center.Mul(1 / count), true
+}
+
+// pixelPoint returns the point of part in pixel space if its score is above ThresholdPartConfidence
+func (h Human) pixelPoint(part CocoPart, imgW float64, imgH float64) (Point, bool) {
+	bodyPart, found := h.GetPart(part, ThresholdPartConfidence)
+	if !found {
+		return ZP, false
+	}
+	return bodyPart.Point.Scale(imgW, imgH), true
+}
+
+// VectorAngle returns the angle between vector a and b in degrees, false if any of them is zero
+func VectorAngle(a Point, b Point) (float64, bool) {
+	na, nb := a.Norm(), b.Norm()
+	if na <= 1e-15 || nb <= 1e-15 {
+		return 0, false
+	}
+	cos := math.Max(-1, math.Min(1, a.Dot(b)/(na*nb)))
+	return math.Acos(cos) * 180 / math.Pi, true
+}
+
+func toMeasure(value float64, valid bool) Measure {
+	return Measure{Value: value, Valid: valid}
+}
@@ -0,0 +1,145 @@
+package openpose
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHuman_JointAngle(t *testing.T) {
+	const imgW, imgH = 400, 300
+	tests := []struct {
+		name   string
+		joint  CocoPart
+		points map[CocoPart]Point
+		want   float64
+		valid  bool
+	}{
+		{"extended elbow", CocoPartRElbow, map[CocoPart]Point{
+			CocoPartRShoulder: Pt(100, 100), CocoPartRElbow: Pt(100, 150), CocoPartRWrist: Pt(100, 200),
+		}, 180, true},
+		{"bent elbow", CocoPartLElbow, map[CocoPart]Point{
+			CocoPartLShoulder: Pt(100, 100), CocoPartLElbow: Pt(100, 150), CocoPartLWrist: Pt(150, 150),
+		}, 90, true},
+		{"bent knee", CocoPartRKnee, map[CocoPart]Point{
+			CocoPartRHip: Pt(100, 100), CocoPartRKnee: Pt(150, 150), CocoPartRAnkle: Pt(100, 200),
+		}, 90, true},
+		{"unsupported joint", CocoPartNose, map[CocoPart]Point{
+			CocoPartNeck: Pt(100, 100), CocoPartNose: Pt(100, 80), CocoPartREye: Pt(95, 75),
+		}, 0, false},
+		{"missing neighbour", CocoPartRElbow, map[CocoPart]Point{
+			CocoPartRShoulder: Pt(100, 100), CocoPartRElbow: Pt(100, 150),
+		}, 0, false},
+		{"missing joint", CocoPartRElbow, map[CocoPart]Point{
+			CocoPartRShoulder: Pt(100, 100), CocoPartRWrist: Pt(100, 200),
+		}, 0, false},
+		{"neighbour on the joint", CocoPartRElbow, map[CocoPart]Point{
+			CocoPartRShoulder: Pt(100, 100), CocoPartRElbow: Pt(100, 150), CocoPartRWrist: Pt(100, 150),
+		}, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := pixelHuman(tt.points, imgW, imgH).JointAngle(tt.joint, imgW, imgH)
+		assert.Equal(t, tt.valid, ok, tt.name)
+		assert.InDelta(t, tt.want, got, 1e-9, tt.name)
+	}
+	// parts below ThresholdPartConfidence are missing
+	h := pixelHuman(map[CocoPart]Point{
+		CocoPartRShoulder: Pt(100, 100), CocoPartRElbow: Pt(100, 150), CocoPartRWrist: Pt(100, 200),
+	}, imgW, imgH)
+	h.Parts[CocoPartRWrist] = NewBodyPart(CocoPartRWrist, Pt(0.25, 0.5), ThresholdPartConfidence)
+	_, ok := h.JointAngle(CocoPartRElbow, imgW, imgH)
+	assert.False(t, ok)
+}
+
+func TestHuman_TrunkInclination(t *testing.T) {
+	const imgW, imgH = 400, 300
+	tests := []struct {
+		name   string
+		points map[CocoPart]Point
+		want   float64
+		valid  bool
+	}{
+		{"upright", map[CocoPart]Point{
+			CocoPartNeck: Pt(100, 50), CocoPartRHip: Pt(90, 150), CocoPartLHip: Pt(110, 150),
+		}, 0, true},
+		{"horizontal", map[CocoPart]Point{
+			CocoPartNeck: Pt(200, 150), CocoPartRHip: Pt(100, 140), CocoPartLHip: Pt(100, 160),
+		}, 90, true},
+		{"upside down", map[CocoPart]Point{
+			CocoPartNeck: Pt(100, 250), CocoPartRHip: Pt(90, 150), CocoPartLHip: Pt(110, 150),
+		}, 180, true},
+		{"shoulders without neck", map[CocoPart]Point{
+			CocoPartRShoulder: Pt(100, 50), CocoPartLShoulder: Pt(200, 50), CocoPartRHip: Pt(0, 150), CocoPartLHip: Pt(100, 150),
+		}, 45, true},
+		{"single hip", map[CocoPart]Point{
+			CocoPartNeck: Pt(100, 50), CocoPartRHip: Pt(150, 100),
+		}, 45, true},
+		{"missing hips", map[CocoPart]Point{
+			CocoPartNeck: Pt(100, 50), CocoPartRShoulder: Pt(80, 50),
+		}, 0, false},
+		{"missing neck and shoulders", map[CocoPart]Point{
+			CocoPartRHip: Pt(90, 150), CocoPartLHip: Pt(110, 150),
+		}, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := pixelHuman(tt.points, imgW, imgH).TrunkInclination(imgW, imgH)
+		assert.Equal(t, tt.valid, ok, tt.name)
+		assert.InDelta(t, tt.want, got, 1e-9, tt.name)
+	}
+}
+
+func TestHuman_Lengths(t *testing.T) {
+	const imgW, imgH = 400, 300
+	h := pixelHuman(map[CocoPart]Point{
+		CocoPartNeck: Pt(100, 50), CocoPartRHip: Pt(90, 150), CocoPartLHip: Pt(110, 150),
+		CocoPartRKnee: Pt(120, 190), CocoPartRAnkle: Pt(120, 250),
+	}, imgW, imgH)
+	torso, ok := h.TorsoLength(imgW, imgH)
+	assert.True(t, ok)
+	assert.InDelta(t, 100, torso, 1e-9)
+	thigh, ok := h.LimbLength(LimbRThigh, imgW, imgH)
+	assert.True(t, ok)
+	assert.InDelta(t, 50, thigh, 1e-9)
+	_, ok = h.LimbLength(LimbLThigh, imgW, imgH)
+	assert.False(t, ok)
+
+	// a single visible hip is taken as the mid hip
+	delete(h.Parts, CocoPartLHip)
+	torso, ok = h.TorsoLength(imgW, imgH)
+	assert.True(t, ok)
+	assert.InDelta(t, Pt(100, 50).Distance(Pt(90, 150)), torso, 1e-9)
+	delete(h.Parts, CocoPartRHip)
+	_, ok = h.TorsoLength(imgW, imgH)
+	assert.False(t, ok)
+	delete(h.Parts, CocoPartNeck)
+	h.Parts[CocoPartRHip] = NewBodyPart(CocoPartRHip, Pt(90.0/imgW, 150.0/imgH), 0.9)
+	_, ok = h.TorsoLength(imgW, imgH)
+	assert.False(t, ok)
+}
+
+func TestKinematics_Ratio(t *testing.T) {
+	const imgW, imgH = 400, 300
+	k := pixelHuman(map[CocoPart]Point{
+		CocoPartNeck: Pt(100, 50), CocoPartRHip: Pt(90, 150), CocoPartLHip: Pt(110, 150),
+		CocoPartRKnee: Pt(120, 190), CocoPartRAnkle: Pt(120, 290),
+		CocoPartLKnee: Pt(110, 150),
+	}, imgW, imgH).Kinematics(imgW, imgH)
+	assert.Len(t, k.Angles, len(JointAngleParts))
+	assert.Len(t, k.Lengths, len(Limbs))
+	assert.True(t, k.TorsoLength.Valid)
+	assert.InDelta(t, 100, k.TorsoLength.Value, 1e-9)
+	assert.True(t, k.TrunkInclination.Valid)
+	assert.InDelta(t, 0, k.TrunkInclination.Value, 1e-9)
+	assert.False(t, k.Angles[CocoPartRElbow].Valid)
+	assert.True(t, k.Angles[CocoPartRKnee].Valid)
+
+	ratio := k.Ratio(LimbRShin, LimbRThigh)
+	assert.True(t, ratio.Valid)
+	assert.InDelta(t, 2, ratio.Value, 1e-9)
+	// missing limbs
+	assert.Equal(t, Measure{}, k.Ratio(LimbRShin, LimbRUpperArm))
+	assert.Equal(t, Measure{}, k.Ratio(LimbRUpperArm, LimbRShin))
+	// zero length limbs, the left knee is on the left hip
+	assert.True(t, k.Lengths[LimbLThigh].Valid)
+	assert.Equal(t, Measure{}, k.Ratio(LimbRShin, LimbLThigh))
+}
@@ -0,0 +1,2 @@
+// Package lifting lifts 2D keypoints to root relative 3D joints by a small fully connected network
+package lifting
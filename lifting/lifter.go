@@ -0,0 +1,107 @@
+package lifting
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/calibration"
+)
+
+// Model input and output sizes, x and y of each part followed by visibility of each part in,
+// x, y and z of each part out
+const (
+	InputSize  = openpose.TotalBodyParts * 3
+	OutputSize = openpose.TotalBodyParts * 3
+)
+
+// Joint represents a lifted joint
+type Joint struct {
+	// Point root relative point in model units, x right, y down and z away from the camera
+	Point calibration.Vec3
+	// Visible the 2D part is visible, otherwise the joint is hallucinated by the model
+	Visible bool
+}
+
+// Pose represents a lifted 3D pose
+type Pose struct {
+	// Joints root relative joints of all parts, the root is hip midpoint
+	Joints map[openpose.CocoPart]Joint
+	// Scale torso length in pixels the 2D input is normalized by
+	Scale float64
+	// Root root in pixels
+	Root openpose.Point
+}
+
+// Lifter lifts humans to 3D poses
+type Lifter struct {
+	model     *Model
+	threshold float32
+}
+
+// NewLifter returns a new Lifter of model with InputSize inputs and OutputSize outputs
+func NewLifter(model *Model) (*Lifter, error) {
+	if err := model.Validate(); err != nil {
+		return nil, err
+	}
+	if model.In() != InputSize || model.Out() != OutputSize {
+		return nil, fmt.Errorf("model size %dx%d mismatches %dx%d", model.Out(), model.In(), OutputSize, InputSize)
+	}
+	return &Lifter{
+		model:     model,
+		threshold: openpose.ThresholdPartConfidence,
+	}, nil
+}
+
+// Normalize returns model input of human in pixel space compared to img size (w, h), its root and scale.
+// Visible parts are relative to hip midpoint and divided by neck to hip midpoint length, hidden parts are zeros
+func Normalize(h openpose.Human, imgW float64, imgH float64, threshold float32) ([]float32, openpose.Point, float64, error) {
+	root, found := h.PixelCenter([]openpose.CocoPart{openpose.CocoPartRHip, openpose.CocoPartLHip}, imgW, imgH)
+	if !found {
+		return nil, root, 0, errors.New("hips not visible")
+	}
+	neck, found := h.GetPart(openpose.CocoPartNeck, threshold)
+	if !found {
+		return nil, root, 0, errors.New("neck not visible")
+	}
+	scale := neck.Point.Scale(imgW, imgH).Distance(root)
+	if scale <= 1e-9 {
+		return nil, root, 0, errors.New("degenerate torso")
+	}
+	ret := make([]float32, InputSize)
+	for part := 0; part < openpose.TotalBodyParts; part++ {
+		bp, found := h.GetPart(openpose.CocoPart(part), threshold)
+		if !found {
+			continue
+		}
+		p := bp.Point.Scale(imgW, imgH).Sub(root).Mul(1 / scale)
+		ret[part*2] = float32(p.X)
+		ret[part*2+1] = float32(p.Y)
+		ret[openpose.TotalBodyParts*2+part] = 1
+	}
+	return ret, root, scale, nil
+}
+
+// Lift returns root relative 3D pose of human in pixel space compared to img size (w, h)
+func (l *Lifter) Lift(h openpose.Human, imgW float64, imgH float64) (Pose, error) {
+	input, root, scale, err := Normalize(h, imgW, imgH, l.threshold)
+	if err != nil {
+		return Pose{}, err
+	}
+	output, err := l.model.Forward(input)
+	if err != nil {
+		return Pose{}, err
+	}
+	ret := Pose{
+		Joints: make(map[openpose.CocoPart]Joint, openpose.TotalBodyParts),
+		Scale:  scale,
+		Root:   root,
+	}
+	for part := 0; part < openpose.TotalBodyParts; part++ {
+		ret.Joints[openpose.CocoPart(part)] = Joint{
+			Point:   calibration.Vec3{float64(output[part*3]), float64(output[part*3+1]), float64(output[part*3+2])},
+			Visible: input[openpose.TotalBodyParts*2+part] > 0,
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,161 @@
+package lifting
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/internal/posetest"
+)
+
+// fixture represents a pose of testdata/fixtures.json lifted by the seeded model of tinyModel, regenerated by TestTestdata
+type fixture struct {
+	Name      string       `json:"name"`
+	Width     float64      `json:"width"`
+	Height    float64      `json:"height"`
+	Keypoints [][3]float64 `json:"keypoints"`
+	Joints    [][3]float64 `json:"joints"`
+}
+
+func TestLifter_Fixtures(t *testing.T) {
+	model, err := LoadModelFile("testdata/tiny.mlp")
+	if !assert.NoError(t, err) {
+		return
+	}
+	lifter, err := NewLifter(model)
+	if !assert.NoError(t, err) {
+		return
+	}
+	buf, err := os.ReadFile("testdata/fixtures.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var fixtures []fixture
+	if !assert.NoError(t, json.Unmarshal(buf, &fixtures)) {
+		return
+	}
+	assert.NotEmpty(t, fixtures)
+	for _, f := range fixtures {
+		h := openpose.NewHuman()
+		for part, kp := range f.Keypoints {
+			if kp[2] > 0 {
+				h.Parts[openpose.CocoPart(part)] = openpose.NewBodyPart(openpose.CocoPart(part), openpose.Pt(kp[0], kp[1]), float32(kp[2]))
+			}
+		}
+		pose, err := lifter.Lift(*h, f.Width, f.Height)
+		if !assert.NoError(t, err, f.Name) {
+			continue
+		}
+		assert.Len(t, pose.Joints, openpose.TotalBodyParts)
+		for part, want := range f.Joints {
+			j := pose.Joints[openpose.CocoPart(part)]
+			assert.Equal(t, f.Keypoints[part][2] > 0, j.Visible, "%s %s", f.Name, openpose.CocoPart(part))
+			for axis := 0; axis < 3; axis++ {
+				assert.InDelta(t, want[axis], j.Point[axis], 1e-4, "%s %s", f.Name, openpose.CocoPart(part))
+			}
+		}
+	}
+}
+
+func TestLifter_ReLU(t *testing.T) {
+	// a hand computed 2 layer network, hidden units read the normalized wrists
+	hidden := Layer{In: InputSize, Out: 2, Activation: ReLU, Weights: make([]float32, InputSize*2), Bias: []float32{0.1, 0}}
+	// h0 = relu(rwrist.y + lwrist.y + 0.1), h1 = relu(2 * rwrist.y - lwrist.x)
+	hidden.Weights[int(openpose.CocoPartRWrist)*2+1] = 1
+	hidden.Weights[int(openpose.CocoPartLWrist)*2+1] = 1
+	hidden.Weights[InputSize+int(openpose.CocoPartRWrist)*2+1] = 2
+	hidden.Weights[InputSize+int(openpose.CocoPartLWrist)*2] = -1
+	out := Layer{In: 2, Out: OutputSize, Activation: Linear, Weights: make([]float32, 2*OutputSize), Bias: make([]float32, OutputSize)}
+	// rwrist.z = 3 * h0 + 2 * h1 - 1, lwrist.z = 5 * h0 - h1, neck.z = 0.25
+	rz, lz := int(openpose.CocoPartRWrist)*3+2, int(openpose.CocoPartLWrist)*3+2
+	out.Weights[rz*2], out.Weights[rz*2+1], out.Bias[rz] = 3, 2, -1
+	out.Weights[lz*2], out.Weights[lz*2+1] = 5, -1
+	out.Bias[int(openpose.CocoPartNeck)*3+2] = 0.25
+	lifter, err := NewLifter(&Model{Layers: []Layer{hidden, out}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	h := posetest.Human(map[openpose.CocoPart]openpose.Point{
+		openpose.CocoPartNeck:   openpose.Pt(320, 100),
+		openpose.CocoPartRHip:   openpose.Pt(300, 200),
+		openpose.CocoPartLHip:   openpose.Pt(340, 200),
+		openpose.CocoPartRWrist: openpose.Pt(280, 250),
+		openpose.CocoPartLWrist: openpose.Pt(360, 120),
+	}, 640, 480)
+	// rwrist (-0.4, 0.5) and lwrist (0.4, -0.8) normalized, h0 = relu(-0.2) = 0 and h1 = 0.6
+	pose, err := lifter.Lift(h, 640, 480)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.InDelta(t, 0.2, pose.Joints[openpose.CocoPartRWrist].Point[2], 1e-6)
+	assert.InDelta(t, -0.6, pose.Joints[openpose.CocoPartLWrist].Point[2], 1e-6)
+	assert.InDelta(t, 0.25, pose.Joints[openpose.CocoPartNeck].Point[2], 1e-6)
+	assert.InDelta(t, 0, pose.Joints[openpose.CocoPartNose].Point[2], 1e-6)
+}
+
+func TestModel_ReadWrite(t *testing.T) {
+	model, err := LoadModelFile("testdata/tiny.mlp")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, WriteModel(&buf, model))
+	read, err := ReadModel(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, model, read)
+	_, err = ReadModel(bytes.NewReader([]byte("NOPE0000")))
+	assert.Error(t, err)
+	// corrupted headers are rejected before allocating
+	header := func(values ...uint32) []byte {
+		var buf bytes.Buffer
+		buf.Write(modelMagic[:])
+		binary.Write(&buf, binary.LittleEndian, values)
+		return buf.Bytes()
+	}
+	_, err = ReadModel(bytes.NewReader(header(modelVersion, 0xffffffff)))
+	assert.Error(t, err)
+	_, err = ReadModel(bytes.NewReader(header(modelVersion, 0)))
+	assert.Error(t, err)
+	_, err = ReadModel(bytes.NewReader(header(modelVersion, 1, 0xffffffff, 0xffffffff, 0)))
+	assert.Error(t, err)
+	_, err = ReadModel(bytes.NewReader(header(modelVersion, 1, 1<<12, 1<<11, 0)))
+	assert.Error(t, err)
+	_, err = NewLifter(&Model{Layers: []Layer{{In: 2, Out: 1, Weights: []float32{1, 1}, Bias: []float32{0}}}})
+	assert.Error(t, err)
+}
+
+func TestNormalize(t *testing.T) {
+	// a linear model passing the normalized 2D input through as x and y
+	l := Layer{In: InputSize, Out: OutputSize, Weights: make([]float32, InputSize*OutputSize), Bias: make([]float32, OutputSize)}
+	for part := 0; part < openpose.TotalBodyParts; part++ {
+		l.Weights[(part*3)*InputSize+part*2] = 1
+		l.Weights[(part*3+1)*InputSize+part*2+1] = 1
+	}
+	lifter, err := NewLifter(&Model{Layers: []Layer{l}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	h := posetest.Human(map[openpose.CocoPart]openpose.Point{
+		openpose.CocoPartNeck:   openpose.Pt(320, 100),
+		openpose.CocoPartRHip:   openpose.Pt(300, 200),
+		openpose.CocoPartLHip:   openpose.Pt(340, 200),
+		openpose.CocoPartRAnkle: openpose.Pt(300, 400),
+	}, 640, 480)
+	pose, err := lifter.Lift(h, 640, 480)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.InDelta(t, 100, pose.Scale, 1e-6)
+	assert.InDelta(t, -1, pose.Joints[openpose.CocoPartNeck].Point[1], 1e-6)
+	assert.InDelta(t, -0.2, pose.Joints[openpose.CocoPartRAnkle].Point[0], 1e-6)
+	assert.InDelta(t, 2, pose.Joints[openpose.CocoPartRAnkle].Point[1], 1e-6)
+	assert.False(t, pose.Joints[openpose.CocoPartNose].Visible)
+	delete(h.Parts, openpose.CocoPartNeck)
+	_, err = lifter.Lift(h, 640, 480)
+	assert.Error(t, err)
+}
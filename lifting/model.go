@@ -0,0 +1,215 @@
+package lifting
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// modelMagic file signature of model files
+var modelMagic = [4]byte{'O', 'P', 'M', 'L'}
+
+// modelVersion model file format version
+const modelVersion uint32 = 1
+
+// Limits of model files, guarding against allocating from corrupted or hostile headers
+const (
+	// maxLayers max number of layers
+	maxLayers = 64
+	// maxLayerWeights max number of weights of a layer
+	maxLayerWeights = 1 << 22
+)
+
+// Activation represents activation function of a layer
+type Activation uint32
+
+const (
+	// Linear identity activation
+	Linear Activation = iota
+	// ReLU rectified linear activation
+	ReLU
+	// Tanh hyperbolic tangent activation
+	Tanh
+)
+
+// String returns Activation name
+func (a Activation) String() string {
+	switch a {
+	case Linear:
+		return "linear"
+	case ReLU:
+		return "relu"
+	case Tanh:
+		return "tanh"
+	}
+	return "unknown"
+}
+
+// Layer represents a fully connected layer, out = activation(Weights * in + Bias)
+type Layer struct {
+	// In input size
+	In int
+	// Out output size
+	Out int
+	// Activation activation function
+	Activation Activation
+	// Weights Out x In weights in row major order
+	Weights []float32
+	// Bias Out biases
+	Bias []float32
+}
+
+// Model represents a multilayer perceptron
+type Model struct {
+	// Layers layers in order
+	Layers []Layer
+}
+
+// Validate returns error if layers are inconsistent
+func (m *Model) Validate() error {
+	if len(m.Layers) == 0 {
+		return errors.New("model has no layer")
+	}
+	for idx, l := range m.Layers {
+		if l.In <= 0 || l.Out <= 0 {
+			return fmt.Errorf("layer %d: invalid size %dx%d", idx, l.Out, l.In)
+		}
+		if len(l.Weights) != l.In*l.Out || len(l.Bias) != l.Out {
+			return fmt.Errorf("layer %d: invalid weights", idx)
+		}
+		if l.Activation > Tanh {
+			return fmt.Errorf("layer %d: unknown activation %d", idx, l.Activation)
+		}
+		if idx > 0 && m.Layers[idx-1].Out != l.In {
+			return fmt.Errorf("layer %d: input size %d mismatches previous output size %d", idx, l.In, m.Layers[idx-1].Out)
+		}
+	}
+	return nil
+}
+
+// In returns input size
+func (m *Model) In() int {
+	return m.Layers[0].In
+}
+
+// Out returns output size
+func (m *Model) Out() int {
+	return m.Layers[len(m.Layers)-1].Out
+}
+
+// Forward returns output of input x
+func (m *Model) Forward(x []float32) ([]float32, error) {
+	if len(x) != m.In() {
+		return nil, fmt.Errorf("input size %d mismatches model input size %d", len(x), m.In())
+	}
+	for _, l := range m.Layers {
+		out := make([]float32, l.Out)
+		for i := 0; i < l.Out; i++ {
+			sum := l.Bias[i]
+			row := l.Weights[i*l.In : (i+1)*l.In]
+			for j, w := range row {
+				sum += w * x[j]
+			}
+			switch l.Activation {
+			case ReLU:
+				if sum < 0 {
+					sum = 0
+				}
+			case Tanh:
+				sum = float32(math.Tanh(float64(sum)))
+			}
+			out[i] = sum
+		}
+		x = out
+	}
+	return x, nil
+}
+
+// ReadModel reads model of binary format, all values are little endian:
+//
+//	magic "OPML", version uint32, layers uint32, then for each layer
+//	in uint32, out uint32, activation uint32, out*in float32 weights in row major order, out float32 biases
+func ReadModel(r io.Reader) (*Model, error) {
+	br := bufio.NewReader(r)
+	var header struct {
+		Magic   [4]byte
+		Version uint32
+		Layers  uint32
+	}
+	if err := binary.Read(br, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+	if header.Magic != modelMagic {
+		return nil, errors.New("invalid model file")
+	}
+	if header.Version != modelVersion {
+		return nil, fmt.Errorf("unsupported model version %d", header.Version)
+	}
+	if header.Layers == 0 || header.Layers > maxLayers {
+		return nil, fmt.Errorf("invalid number of layers %d", header.Layers)
+	}
+	m := new(Model)
+	for idx := uint32(0); idx < header.Layers; idx++ {
+		var shape [3]uint32
+		if err := binary.Read(br, binary.LittleEndian, &shape); err != nil {
+			return nil, err
+		}
+		if shape[0] == 0 || shape[1] == 0 || uint64(shape[0])*uint64(shape[1]) > maxLayerWeights {
+			return nil, fmt.Errorf("layer %d: invalid size %dx%d", idx, shape[1], shape[0])
+		}
+		l := Layer{
+			In:         int(shape[0]),
+			Out:        int(shape[1]),
+			Activation: Activation(shape[2]),
+			Weights:    make([]float32, shape[0]*shape[1]),
+			Bias:       make([]float32, shape[1]),
+		}
+		if err := binary.Read(br, binary.LittleEndian, l.Weights); err != nil {
+			return nil, err
+		}
+		if err := binary.Read(br, binary.LittleEndian, l.Bias); err != nil {
+			return nil, err
+		}
+		m.Layers = append(m.Layers, l)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// LoadModelFile reads model of binary format from file
+func LoadModelFile(path string) (*Model, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	return ReadModel(fd)
+}
+
+// WriteModel writes model in the binary format of ReadModel
+func WriteModel(w io.Writer, m *Model) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	header := []interface{}{modelMagic, modelVersion, uint32(len(m.Layers))}
+	for _, v := range header {
+		if err := binary.Write(bw, binary.LittleEndian, v); err != nil {
+			return err
+		}
+	}
+	for _, l := range m.Layers {
+		for _, v := range []interface{}{[3]uint32{uint32(l.In), uint32(l.Out), uint32(l.Activation)}, l.Weights, l.Bias} {
+			if err := binary.Write(bw, binary.LittleEndian, v); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
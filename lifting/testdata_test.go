@@ -0,0 +1,156 @@
+package lifting
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bububa/openpose"
+)
+
+// update rewrites testdata/tiny.mlp and testdata/fixtures.json, run go test ./lifting -run TestTestdata -update
+var update = flag.Bool("update", false, "rewrite testdata of the seeded tiny model")
+
+// fixturePoses poses in pixels of a 640x480 image of testdata/fixtures.json, all parts are scored 0.8
+var fixturePoses = []struct {
+	name   string
+	points map[openpose.CocoPart][2]float64
+}{
+	{"standing", map[openpose.CocoPart][2]float64{
+		openpose.CocoPartNose: {320, 100}, openpose.CocoPartNeck: {320, 140},
+		openpose.CocoPartRShoulder: {290, 140}, openpose.CocoPartRElbow: {280, 200}, openpose.CocoPartRWrist: {275, 255},
+		openpose.CocoPartLShoulder: {350, 140}, openpose.CocoPartLElbow: {360, 200}, openpose.CocoPartLWrist: {365, 255},
+		openpose.CocoPartRHip: {305, 260}, openpose.CocoPartRKnee: {303, 350}, openpose.CocoPartRAnkle: {302, 440},
+		openpose.CocoPartLHip: {335, 260}, openpose.CocoPartLKnee: {337, 350}, openpose.CocoPartLAnkle: {338, 440},
+		openpose.CocoPartREye: {314, 94}, openpose.CocoPartLEye: {326, 94}, openpose.CocoPartREar: {306, 98}, openpose.CocoPartLEar: {334, 98},
+	}},
+	{"arms_raised_partial", map[openpose.CocoPart][2]float64{
+		openpose.CocoPartNose: {200, 150}, openpose.CocoPartNeck: {200, 190},
+		openpose.CocoPartRShoulder: {170, 190}, openpose.CocoPartRElbow: {160, 140}, openpose.CocoPartRWrist: {155, 90},
+		openpose.CocoPartLShoulder: {230, 190}, openpose.CocoPartLElbow: {240, 140},
+		openpose.CocoPartRHip: {185, 300}, openpose.CocoPartRKnee: {180, 390},
+		openpose.CocoPartLHip: {215, 300}, openpose.CocoPartLKnee: {222, 390},
+		openpose.CocoPartREye: {194, 144},
+	}},
+}
+
+// tinyModel returns the model of testdata/tiny.mlp, a 54-32-54 perceptron of seeded random weights
+func tinyModel() *Model {
+	// linear congruential generator of values in [-1, 1)
+	seed := uint32(20211017)
+	rnd := func() float32 {
+		seed = seed*1664525 + 1013904223
+		return float32(seed>>8)/float32(1<<24)*2 - 1
+	}
+	sizes := []int{InputSize, 32, OutputSize}
+	m := new(Model)
+	for idx := 1; idx < len(sizes); idx++ {
+		l := Layer{
+			In:         sizes[idx-1],
+			Out:        sizes[idx],
+			Activation: ReLU,
+			Weights:    make([]float32, sizes[idx-1]*sizes[idx]),
+			Bias:       make([]float32, sizes[idx]),
+		}
+		if idx == len(sizes)-1 {
+			l.Activation = Linear
+		}
+		for i := range l.Weights {
+			l.Weights[i] = rnd() * 0.3
+		}
+		for i := range l.Bias {
+			l.Bias[i] = rnd() * 0.1
+		}
+		m.Layers = append(m.Layers, l)
+	}
+	return m
+}
+
+// fixtures returns fixtures of fixturePoses lifted by lifter
+func fixtures(lifter *Lifter) ([]fixture, error) {
+	ret := make([]fixture, 0, len(fixturePoses))
+	for _, pose := range fixturePoses {
+		f := fixture{Name: pose.name, Width: 640, Height: 480, Keypoints: make([][3]float64, openpose.TotalBodyParts)}
+		h := openpose.NewHuman()
+		for part, p := range pose.points {
+			pt := openpose.Pt(p[0]/f.Width, p[1]/f.Height)
+			h.Parts[part] = openpose.NewBodyPart(part, pt, 0.8)
+			f.Keypoints[part] = [3]float64{pt.X, pt.Y, 0.8}
+		}
+		lifted, err := lifter.Lift(*h, f.Width, f.Height)
+		if err != nil {
+			return nil, err
+		}
+		for part := 0; part < openpose.TotalBodyParts; part++ {
+			f.Joints = append(f.Joints, lifted.Joints[openpose.CocoPart(part)].Point)
+		}
+		ret = append(ret, f)
+	}
+	return ret, nil
+}
+
+// encodeFixtures returns fixtures as indented JSON of one line per point, values rounded to 7 decimals
+func encodeFixtures(fixtures []fixture) []byte {
+	points := func(buf *bytes.Buffer, name string, values [][3]float64) {
+		fmt.Fprintf(buf, "    %q: [\n", name)
+		for idx, v := range values {
+			buf.WriteString("      [")
+			for axis, x := range v {
+				if axis > 0 {
+					buf.WriteString(", ")
+				}
+				buf.WriteString(strconv.FormatFloat(math.Round(x*1e7)/1e7, 'f', -1, 64))
+			}
+			buf.WriteString("]")
+			if idx < len(values)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString("    ]")
+	}
+	var buf bytes.Buffer
+	buf.WriteString("[\n")
+	for idx, f := range fixtures {
+		fmt.Fprintf(&buf, "  {\n    \"name\": %q,\n    \"width\": %g,\n    \"height\": %g,\n", f.Name, f.Width, f.Height)
+		points(&buf, "keypoints", f.Keypoints)
+		buf.WriteString(",\n")
+		points(&buf, "joints", f.Joints)
+		buf.WriteString("\n  }")
+		if idx < len(fixtures)-1 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("]\n")
+	return buf.Bytes()
+}
+
+func TestTestdata(t *testing.T) {
+	model := tinyModel()
+	if *update {
+		var buf bytes.Buffer
+		assert.NoError(t, WriteModel(&buf, model))
+		assert.NoError(t, os.WriteFile("testdata/tiny.mlp", buf.Bytes(), 0644))
+		lifter, err := NewLifter(model)
+		if !assert.NoError(t, err) {
+			return
+		}
+		lifted, err := fixtures(lifter)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.NoError(t, os.WriteFile("testdata/fixtures.json", encodeFixtures(lifted), 0644))
+	}
+	// the committed model is the seeded one
+	read, err := LoadModelFile("testdata/tiny.mlp")
+	if assert.NoError(t, err) {
+		assert.Equal(t, model, read)
+	}
+}
@@ -0,0 +1,2 @@
+// Package multiview associates people across calibrated views and triangulates their 3D joints
+package multiview
@@ -0,0 +1,96 @@
+package multiview
+
+import (
+	"errors"
+	"math"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/calibration"
+)
+
+// Observation represents a pixel observation of a point by a camera
+type Observation struct {
+	// Camera observing camera
+	Camera calibration.Camera
+	// Point ideal pinhole pixel point, undistorted if the camera has lens distortion
+	Point openpose.Point
+	// Weight observation weight, e.g. body part score
+	Weight float64
+}
+
+// TriangulatePoint returns world point of at least 2 observations by weighted linear DLT and its
+// weighted mean reprojection error in pixels
+func TriangulatePoint(observations []Observation) (calibration.Vec3, float64, error) {
+	if len(observations) < 2 {
+		return calibration.Vec3{}, 0, errors.New("at least 2 observations required")
+	}
+	// each observation gives x*P3 - P1 = 0 and y*P3 - P2 = 0, solved for X = (x, y, z, 1) by least squares
+	var (
+		ata calibration.Mat3
+		atb calibration.Vec3
+	)
+	for _, o := range observations {
+		p := o.Camera.Projection()
+		// rows are scaled by focal length so the residuals are in normalized image units
+		w := o.Weight / math.Sqrt(o.Camera.Intrinsics.Fx*o.Camera.Intrinsics.Fy)
+		for _, row := range [2][4]float64{
+			{o.Point.X*p[2][0] - p[0][0], o.Point.X*p[2][1] - p[0][1], o.Point.X*p[2][2] - p[0][2], o.Point.X*p[2][3] - p[0][3]},
+			{o.Point.Y*p[2][0] - p[1][0], o.Point.Y*p[2][1] - p[1][1], o.Point.Y*p[2][2] - p[1][2], o.Point.Y*p[2][3] - p[1][3]},
+		} {
+			for i := 0; i < 3; i++ {
+				for j := 0; j < 3; j++ {
+					ata[i][j] += w * w * row[i] * row[j]
+				}
+				atb[i] -= w * w * row[i] * row[3]
+			}
+		}
+	}
+	inv, ok := ata.Inverse()
+	if !ok {
+		return calibration.Vec3{}, 0, errors.New("degenerate observations")
+	}
+	ret := inv.MulVec(atb)
+	var errSum, weightSum float64
+	for _, o := range observations {
+		projected, ok := o.Camera.Project(ret)
+		if !ok {
+			return ret, math.Inf(1), nil
+		}
+		errSum += o.Weight * projected.Distance(o.Point)
+		weightSum += o.Weight
+	}
+	if weightSum <= 0 {
+		return ret, 0, nil
+	}
+	return ret, errSum / weightSum, nil
+}
+
+// Joint represents a triangulated joint
+type Joint struct {
+	// Part body part
+	Part openpose.CocoPart
+	// Point world point
+	Point calibration.Vec3
+	// Views number of views the joint is triangulated from
+	Views int
+	// Error weighted mean reprojection error in pixels
+	Error float64
+	// Score mean score of the observations
+	Score float64
+}
+
+// Skeleton represents a 3D skeleton of a person seen in multiple views
+type Skeleton struct {
+	// Joints triangulated joints
+	Joints map[openpose.CocoPart]Joint
+	// Humans index of the human in each view, view index to human index
+	Humans map[int]int
+	// Error mean reprojection error of joints in pixels
+	Error float64
+}
+
+// Joint returns triangulated joint of part
+func (s Skeleton) Joint(part openpose.CocoPart) (Joint, bool) {
+	j, found := s.Joints[part]
+	return j, found
+}
@@ -0,0 +1,266 @@
+package multiview
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/calibration"
+)
+
+// Config represents triangulator params
+type Config struct {
+	// MaxEpipolarDistance max mean pixel distance of joints to epipolar lines of the same person in another view
+	MaxEpipolarDistance float64
+	// MinCommonParts min visible parts two views of the same person share
+	MinCommonParts int
+	// MinViews min views a person is triangulated from
+	MinViews int
+	// PartThreshold min body part score to use
+	PartThreshold float32
+	// MaxReprojectionError joints with larger reprojection error in pixels are dropped, unlimited if 0
+	MaxReprojectionError float64
+}
+
+// DefaultConfig returns default triangulator params
+func DefaultConfig() Config {
+	return Config{
+		MaxEpipolarDistance:  20,
+		MinCommonParts:       3,
+		MinViews:             2,
+		PartThreshold:        openpose.ThresholdPartConfidence,
+		MaxReprojectionError: 50,
+	}
+}
+
+// Triangulator associates humans of synchronized views and triangulates their joints
+type Triangulator struct {
+	cfg     Config
+	cameras []calibration.Camera
+	// fundamentals[a][b] maps pixel points of view a to epipolar lines in view b
+	fundamentals [][]calibration.Mat3
+}
+
+// NewTriangulator returns a new Triangulator of at least 2 cameras
+func NewTriangulator(cameras []calibration.Camera, cfg Config) (*Triangulator, error) {
+	if len(cameras) < 2 {
+		return nil, errors.New("at least 2 cameras required")
+	}
+	fundamentals := make([][]calibration.Mat3, len(cameras))
+	for a := range cameras {
+		fundamentals[a] = make([]calibration.Mat3, len(cameras))
+		for b := range cameras {
+			if a == b {
+				continue
+			}
+			f, err := calibration.Fundamental(cameras[a], cameras[b])
+			if err != nil {
+				return nil, err
+			}
+			fundamentals[a][b] = f
+		}
+	}
+	return &Triangulator{
+		cfg:          cfg,
+		cameras:      cameras,
+		fundamentals: fundamentals,
+	}, nil
+}
+
+// candidate represents a human of a view
+type candidate struct {
+	view  int
+	human int
+}
+
+// edge represents epipolar cost of two candidates of different views
+type edge struct {
+	a    candidate
+	b    candidate
+	cost float64
+}
+
+// Cost returns mean symmetric epipolar distance in pixels of common parts of human a in view va
+// and human b in view vb, false if they share less than MinCommonParts
+func (t *Triangulator) Cost(va int, a openpose.Human, vb int, b openpose.Human) (float64, bool) {
+	camA, camB := t.cameras[va], t.cameras[vb]
+	var (
+		sum   float64
+		count int
+	)
+	for part := range a.Parts {
+		pa, foundA := a.GetPart(part, t.cfg.PartThreshold)
+		pb, foundB := b.GetPart(part, t.cfg.PartThreshold)
+		if !foundA || !foundB {
+			continue
+		}
+		xa, xb := camA.Undistort(camA.Pixel(pa.Point)), camB.Undistort(camB.Pixel(pb.Point))
+		sum += (calibration.EpipolarDistance(t.fundamentals[va][vb], xa, xb) + calibration.EpipolarDistance(t.fundamentals[vb][va], xb, xa)) / 2
+		count++
+	}
+	if count < t.cfg.MinCommonParts || count == 0 {
+		return 0, false
+	}
+	return sum / float64(count), true
+}
+
+// Associate groups humans of views into people, views[i] are humans of camera i.
+// Cross view pairs are merged greedily by ascending epipolar cost, a group takes at most one human
+// of each view and all its members must be epipolar consistent. Each group maps view index to human index
+func (t *Triangulator) Associate(views [][]openpose.Human) []map[int]int {
+	var edges []edge
+	for va := 0; va < len(views) && va < len(t.cameras); va++ {
+		for vb := va + 1; vb < len(views) && vb < len(t.cameras); vb++ {
+			for ia, a := range views[va] {
+				for ib, b := range views[vb] {
+					cost, ok := t.Cost(va, a, vb, b)
+					if !ok || cost > t.cfg.MaxEpipolarDistance {
+						continue
+					}
+					edges = append(edges, edge{a: candidate{va, ia}, b: candidate{vb, ib}, cost: cost})
+				}
+			}
+		}
+	}
+	sort.SliceStable(edges, func(i, j int) bool {
+		return edges[i].cost < edges[j].cost
+	})
+	consistent := make(map[[2]candidate]bool, len(edges))
+	for _, e := range edges {
+		consistent[[2]candidate{e.a, e.b}] = true
+		consistent[[2]candidate{e.b, e.a}] = true
+	}
+	groupOf := make(map[candidate]int)
+	var groups []map[int]int
+	for _, e := range edges {
+		ga, foundA := groupOf[e.a]
+		gb, foundB := groupOf[e.b]
+		switch {
+		case !foundA && !foundB:
+			groupOf[e.a], groupOf[e.b] = len(groups), len(groups)
+			groups = append(groups, map[int]int{e.a.view: e.a.human, e.b.view: e.b.human})
+		case foundA && foundB:
+			if ga == gb || !mergeable(groups[ga], groups[gb], consistent) {
+				continue
+			}
+			for view, human := range groups[gb] {
+				groups[ga][view] = human
+				groupOf[candidate{view, human}] = ga
+			}
+			groups[gb] = nil
+		case foundA:
+			if join(groups[ga], e.b, consistent) {
+				groupOf[e.b] = ga
+			}
+		case foundB:
+			if join(groups[gb], e.a, consistent) {
+				groupOf[e.a] = gb
+			}
+		}
+	}
+	ret := make([]map[int]int, 0, len(groups))
+	for _, g := range groups {
+		if len(g) >= t.cfg.MinViews {
+			ret = append(ret, g)
+		}
+	}
+	return ret
+}
+
+// join adds c to group if its view is free and it is consistent with all members
+func join(group map[int]int, c candidate, consistent map[[2]candidate]bool) bool {
+	if _, found := group[c.view]; found {
+		return false
+	}
+	for view, human := range group {
+		if !consistent[[2]candidate{{view, human}, c}] {
+			return false
+		}
+	}
+	group[c.view] = c.human
+	return true
+}
+
+// mergeable returns groups a and b have no common view and all their members are consistent
+func mergeable(a map[int]int, b map[int]int, consistent map[[2]candidate]bool) bool {
+	for viewA, humanA := range a {
+		if _, found := b[viewA]; found {
+			return false
+		}
+		for viewB, humanB := range b {
+			if !consistent[[2]candidate{{viewA, humanA}, {viewB, humanB}}] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Triangulate associates humans of views and returns their 3D skeletons, views[i] are humans of camera i
+func (t *Triangulator) Triangulate(views [][]openpose.Human) []Skeleton {
+	groups := t.Associate(views)
+	ret := make([]Skeleton, 0, len(groups))
+	for _, group := range groups {
+		humans := make(map[int]openpose.Human, len(group))
+		for view, idx := range group {
+			humans[view] = views[view][idx]
+		}
+		skeleton := t.TriangulateHumans(humans)
+		if len(skeleton.Joints) == 0 {
+			continue
+		}
+		skeleton.Humans = group
+		ret = append(ret, skeleton)
+	}
+	return ret
+}
+
+// TriangulateHumans returns 3D skeleton of a person from its humans of views, view index to human.
+// Each joint is triangulated from views it is visible in, weighted by body part score
+func (t *Triangulator) TriangulateHumans(humans map[int]openpose.Human) Skeleton {
+	ret := Skeleton{
+		Joints: make(map[openpose.CocoPart]Joint),
+	}
+	var errSum float64
+	for part := openpose.CocoPartNose; part < openpose.CocoPartBackground; part++ {
+		var (
+			observations []Observation
+			score        float64
+		)
+		for view, cam := range t.cameras {
+			h, found := humans[view]
+			if !found {
+				continue
+			}
+			bp, found := h.GetPart(part, t.cfg.PartThreshold)
+			if !found {
+				continue
+			}
+			observations = append(observations, Observation{
+				Camera: cam,
+				Point:  cam.Undistort(cam.Pixel(bp.Point)),
+				Weight: float64(bp.Score),
+			})
+			score += float64(bp.Score)
+		}
+		if len(observations) < 2 {
+			continue
+		}
+		point, reprojection, err := TriangulatePoint(observations)
+		if err != nil || t.cfg.MaxReprojectionError > 0 && reprojection > t.cfg.MaxReprojectionError {
+			continue
+		}
+		ret.Joints[part] = Joint{
+			Part:  part,
+			Point: point,
+			Views: len(observations),
+			Error: reprojection,
+			Score: score / float64(len(observations)),
+		}
+		errSum += reprojection
+	}
+	if len(ret.Joints) > 0 {
+		ret.Error = errSum / float64(len(ret.Joints))
+	}
+	return ret
+}
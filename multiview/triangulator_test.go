@@ -0,0 +1,123 @@
+package multiview
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/calibration"
+	"github.com/bububa/openpose/internal/posetest"
+)
+
+// skeleton is a standing person in metres, y up, centered at origin on the floor
+var skeleton = map[openpose.CocoPart]calibration.Vec3{
+	openpose.CocoPartNose:      {0, 1.6, 0.1},
+	openpose.CocoPartNeck:      {0, 1.45, 0},
+	openpose.CocoPartRShoulder: {-0.2, 1.45, 0},
+	openpose.CocoPartLShoulder: {0.2, 1.45, 0},
+	openpose.CocoPartRElbow:    {-0.25, 1.15, 0.05},
+	openpose.CocoPartLElbow:    {0.25, 1.15, 0.05},
+	openpose.CocoPartRHip:      {-0.12, 0.9, 0},
+	openpose.CocoPartLHip:      {0.12, 0.9, 0},
+	openpose.CocoPartRKnee:     {-0.12, 0.5, 0.05},
+	openpose.CocoPartLKnee:     {0.12, 0.5, 0.05},
+	openpose.CocoPartRAnkle:    {-0.12, 0.08, 0},
+	openpose.CocoPartLAnkle:    {0.12, 0.08, 0},
+}
+
+// lookAt returns camera at eye looking at target, image y axis points down
+func lookAt(name string, eye calibration.Vec3, target calibration.Vec3) calibration.Camera {
+	z := target.Sub(eye)
+	z = z.Mul(1 / z.Norm())
+	x := z.Cross(calibration.Vec3{0, -1, 0})
+	x = x.Mul(1 / x.Norm())
+	y := z.Cross(x)
+	r := calibration.Mat3{x, y, z}
+	return calibration.Camera{
+		Name:        name,
+		Width:       640,
+		Height:      480,
+		Intrinsics:  calibration.Intrinsics{Fx: 500, Fy: 500, Cx: 320, Cy: 240},
+		Rotation:    &r,
+		Translation: r.MulVec(eye).Mul(-1),
+	}
+}
+
+// project returns human of person at offset seen by cam
+func project(cam calibration.Camera, offset calibration.Vec3) openpose.Human {
+	points := make(map[openpose.CocoPart]openpose.Point, len(skeleton))
+	for part, p := range skeleton {
+		points[part], _ = cam.Project(p.Add(offset))
+	}
+	h := posetest.Human(points, cam.Width, cam.Height)
+	h.Score = 0.9
+	return h
+}
+
+func TestTriangulator_Triangulate(t *testing.T) {
+	cameras := []calibration.Camera{
+		lookAt("front", calibration.Vec3{0, 1.5, 6}, calibration.Vec3{0, 1, 0}),
+		lookAt("left", calibration.Vec3{-5, 2, 3}, calibration.Vec3{0, 1, 0}),
+		lookAt("right", calibration.Vec3{5, 2.5, 2}, calibration.Vec3{0, 1, 0}),
+	}
+	tr, err := NewTriangulator(cameras, DefaultConfig())
+	if !assert.NoError(t, err) {
+		return
+	}
+	offsets := []calibration.Vec3{{-1, 0, 0}, {1.2, 0, -0.5}}
+	views := make([][]openpose.Human, len(cameras))
+	for idx, cam := range cameras {
+		for _, offset := range offsets {
+			views[idx] = append(views[idx], project(cam, offset))
+		}
+	}
+	// humans are detected in any order
+	views[1][0], views[1][1] = views[1][1], views[1][0]
+	// the right camera misses the left knee of the first person
+	delete(views[2][0].Parts, openpose.CocoPartLKnee)
+	skeletons := tr.Triangulate(views)
+	if !assert.Len(t, skeletons, 2) {
+		return
+	}
+	for _, s := range skeletons {
+		assert.Len(t, s.Humans, 3)
+		person := s.Humans[0]
+		assert.Equal(t, 1-person, s.Humans[1])
+		assert.Equal(t, person, s.Humans[2])
+		assert.Len(t, s.Joints, len(skeleton))
+		assert.InDelta(t, 0, s.Error, 1e-6)
+		for part, want := range skeleton {
+			want = want.Add(offsets[person])
+			j, found := s.Joint(part)
+			if assert.True(t, found) {
+				assert.InDelta(t, 0, j.Point.Distance(want), 1e-6, "%s", part)
+			}
+		}
+		if person == 0 {
+			assert.Equal(t, 2, s.Joints[openpose.CocoPartLKnee].Views)
+		}
+	}
+}
+
+func TestTriangulatePoint(t *testing.T) {
+	front := lookAt("front", calibration.Vec3{0, 1.5, 6}, calibration.Vec3{0, 1, 0})
+	left := lookAt("left", calibration.Vec3{-5, 2, 3}, calibration.Vec3{0, 1, 0})
+	right := lookAt("right", calibration.Vec3{5, 2.5, 2}, calibration.Vec3{0, 1, 0})
+	want := calibration.Vec3{0.3, 1.2, 0.4}
+	var observations []Observation
+	for _, cam := range []calibration.Camera{front, left, right} {
+		px, _ := cam.Project(want)
+		observations = append(observations, Observation{Camera: cam, Point: px, Weight: 1})
+	}
+	// a bad low score observation barely moves the point
+	observations[2].Point = observations[2].Point.Add(openpose.Pt(30, 0))
+	observations[2].Weight = 0.05
+	got, reprojection, err := TriangulatePoint(observations)
+	assert.NoError(t, err)
+	assert.Less(t, got.Distance(want), 0.005)
+	assert.False(t, math.IsInf(reprojection, 1))
+	_, _, err = TriangulatePoint(observations[:1])
+	assert.Error(t, err)
+}
@@ -0,0 +1,74 @@
+package posture
+
+import (
+	"sort"
+
+	"github.com/bububa/openpose"
+)
+
+// Posture represents posture label
+type Posture string
+
+const (
+	// Standing standing upright
+	Standing Posture = "standing"
+	// Sitting sitting
+	Sitting Posture = "sitting"
+	// Lying lying down
+	Lying Posture = "lying"
+	// Crouching crouching or squatting
+	Crouching Posture = "crouching"
+	// RightArmRaised only right arm raised
+	RightArmRaised Posture = "right_arm_raised"
+	// LeftArmRaised only left arm raised
+	LeftArmRaised Posture = "left_arm_raised"
+	// BothArmsRaised both arms raised
+	BothArmsRaised Posture = "both_arms_raised"
+	// HandsOnHips both hands on hips
+	HandsOnHips Posture = "hands_on_hips"
+	// TPose arms stretched out horizontally
+	TPose Posture = "t_pose"
+)
+
+// Label represents classified posture with confidence in [0, 1]
+type Label struct {
+	Posture    Posture `json:"posture"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Classifier represents rule based posture classifier
+type Classifier struct {
+	cfg   Config
+	rules []Rule
+}
+
+// NewClassifier returns a new Classifier with config and rules, BuiltinRules are used without rules
+func NewClassifier(cfg Config, rules ...Rule) *Classifier {
+	if len(rules) == 0 {
+		rules = BuiltinRules
+	}
+	return &Classifier{
+		cfg:   cfg,
+		rules: rules,
+	}
+}
+
+// Config returns classifier config
+func (c *Classifier) Config() Config {
+	return c.cfg
+}
+
+// Classify returns posture labels of human in pixel space compared to img size (w, h), sorted by confidence
+func (c *Classifier) Classify(h openpose.Human, imgW float64, imgH float64) []Label {
+	features := NewFeatures(h, imgW, imgH)
+	labels := make([]Label, 0, len(c.rules))
+	for _, rule := range c.rules {
+		confidence, ok := rule.Score(features, c.cfg)
+		if !ok || confidence < c.cfg.MinConfidence {
+			continue
+		}
+		labels = append(labels, Label{Posture: rule.Posture, Confidence: confidence})
+	}
+	sort.SliceStable(labels, func(i, j int) bool { return labels[i].Confidence > labels[j].Confidence })
+	return labels
+}
@@ -0,0 +1,202 @@
+package posture
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/internal/posetest"
+)
+
+func standingLegs() map[openpose.CocoPart]openpose.Point {
+	return map[openpose.CocoPart]openpose.Point{
+		openpose.CocoPartNose:      openpose.Pt(50, 10),
+		openpose.CocoPartNeck:      openpose.Pt(50, 20),
+		openpose.CocoPartRShoulder: openpose.Pt(42, 20),
+		openpose.CocoPartLShoulder: openpose.Pt(58, 20),
+		openpose.CocoPartRHip:      openpose.Pt(45, 50),
+		openpose.CocoPartLHip:      openpose.Pt(55, 50),
+		openpose.CocoPartRKnee:     openpose.Pt(45, 70),
+		openpose.CocoPartLKnee:     openpose.Pt(55, 70),
+		openpose.CocoPartRAnkle:    openpose.Pt(45, 90),
+		openpose.CocoPartLAnkle:    openpose.Pt(55, 90),
+	}
+}
+
+func labelsOf(labels []Label) []Posture {
+	ret := make([]Posture, 0, len(labels))
+	for _, label := range labels {
+		ret = append(ret, label.Posture)
+	}
+	return ret
+}
+
+func TestClassify_StandingWithArmsDown(t *testing.T) {
+	points := standingLegs()
+	points[openpose.CocoPartRElbow] = openpose.Pt(41, 35)
+	points[openpose.CocoPartRWrist] = openpose.Pt(41, 48)
+	points[openpose.CocoPartLElbow] = openpose.Pt(59, 35)
+	points[openpose.CocoPartLWrist] = openpose.Pt(59, 48)
+	c := NewClassifier(DefaultConfig())
+	labels := labelsOf(c.Classify(posetest.Human(points, 100, 100), 100, 100))
+	assert.Contains(t, labels, Standing)
+	assert.NotContains(t, labels, Sitting)
+	assert.NotContains(t, labels, Lying)
+	assert.NotContains(t, labels, BothArmsRaised)
+}
+
+func TestClassify_TPoseAndRightArmRaised(t *testing.T) {
+	points := standingLegs()
+	points[openpose.CocoPartRElbow] = openpose.Pt(30, 20)
+	points[openpose.CocoPartRWrist] = openpose.Pt(18, 20)
+	points[openpose.CocoPartLElbow] = openpose.Pt(70, 20)
+	points[openpose.CocoPartLWrist] = openpose.Pt(82, 20)
+	c := NewClassifier(DefaultConfig())
+	assert.Contains(t, labelsOf(c.Classify(posetest.Human(points, 100, 100), 100, 100)), TPose)
+
+	points[openpose.CocoPartRElbow] = openpose.Pt(42, 8)
+	points[openpose.CocoPartRWrist] = openpose.Pt(42, 0)
+	labels := labelsOf(c.Classify(posetest.Human(points, 100, 100), 100, 100))
+	assert.Contains(t, labels, RightArmRaised)
+	assert.NotContains(t, labels, TPose)
+	assert.NotContains(t, labels, BothArmsRaised)
+}
+
+func TestClassify_Lying(t *testing.T) {
+	points := map[openpose.CocoPart]openpose.Point{
+		openpose.CocoPartNeck:   openpose.Pt(20, 80),
+		openpose.CocoPartRHip:   openpose.Pt(50, 78),
+		openpose.CocoPartLHip:   openpose.Pt(50, 82),
+		openpose.CocoPartRKnee:  openpose.Pt(70, 78),
+		openpose.CocoPartRAnkle: openpose.Pt(90, 78),
+	}
+	c := NewClassifier(DefaultConfig())
+	labels := labelsOf(c.Classify(posetest.Human(points, 100, 100), 100, 100))
+	assert.Contains(t, labels, Lying)
+	assert.NotContains(t, labels, Standing)
+}
+
+func TestClassify_Sitting(t *testing.T) {
+	// side view, thighs horizontal and shins vertical
+	points := map[openpose.CocoPart]openpose.Point{
+		openpose.CocoPartNeck:      openpose.Pt(50, 20),
+		openpose.CocoPartRShoulder: openpose.Pt(50, 20),
+		openpose.CocoPartLShoulder: openpose.Pt(50, 20),
+		openpose.CocoPartRHip:      openpose.Pt(50, 50),
+		openpose.CocoPartLHip:      openpose.Pt(50, 50),
+		openpose.CocoPartRKnee:     openpose.Pt(72, 50),
+		openpose.CocoPartLKnee:     openpose.Pt(72, 50),
+		openpose.CocoPartRAnkle:    openpose.Pt(72, 75),
+		openpose.CocoPartLAnkle:    openpose.Pt(72, 75),
+	}
+	c := NewClassifier(DefaultConfig())
+	labels := labelsOf(c.Classify(posetest.Human(points, 100, 100), 100, 100))
+	assert.Contains(t, labels, Sitting)
+	assert.NotContains(t, labels, Standing)
+	assert.NotContains(t, labels, Crouching)
+	assert.NotContains(t, labels, Lying)
+}
+
+func TestClassify_Crouching(t *testing.T) {
+	// side view, hips down close to the ankles
+	points := map[openpose.CocoPart]openpose.Point{
+		openpose.CocoPartNeck:      openpose.Pt(50, 40),
+		openpose.CocoPartRShoulder: openpose.Pt(50, 40),
+		openpose.CocoPartLShoulder: openpose.Pt(50, 40),
+		openpose.CocoPartRHip:      openpose.Pt(50, 65),
+		openpose.CocoPartLHip:      openpose.Pt(50, 65),
+		openpose.CocoPartRKnee:     openpose.Pt(65, 60),
+		openpose.CocoPartLKnee:     openpose.Pt(65, 60),
+		openpose.CocoPartRAnkle:    openpose.Pt(52, 72),
+		openpose.CocoPartLAnkle:    openpose.Pt(52, 72),
+	}
+	c := NewClassifier(DefaultConfig())
+	labels := labelsOf(c.Classify(posetest.Human(points, 100, 100), 100, 100))
+	assert.Contains(t, labels, Crouching)
+	assert.NotContains(t, labels, Sitting)
+	assert.NotContains(t, labels, Standing)
+}
+
+func TestClassify_HandsOnHips(t *testing.T) {
+	points := standingLegs()
+	points[openpose.CocoPartRElbow] = openpose.Pt(32, 35)
+	points[openpose.CocoPartRWrist] = openpose.Pt(44, 48)
+	points[openpose.CocoPartLElbow] = openpose.Pt(68, 35)
+	points[openpose.CocoPartLWrist] = openpose.Pt(56, 48)
+	c := NewClassifier(DefaultConfig())
+	labels := labelsOf(c.Classify(posetest.Human(points, 100, 100), 100, 100))
+	assert.Contains(t, labels, HandsOnHips)
+	assert.Contains(t, labels, Standing)
+	assert.NotContains(t, labels, TPose)
+
+	// a single hand on hip
+	points[openpose.CocoPartLElbow] = openpose.Pt(59, 35)
+	points[openpose.CocoPartLWrist] = openpose.Pt(59, 48)
+	labels = labelsOf(c.Classify(posetest.Human(points, 100, 100), 100, 100))
+	assert.NotContains(t, labels, HandsOnHips)
+	delete(points, openpose.CocoPartLWrist)
+	_, ok := scoreHandsOnHips(NewFeatures(posetest.Human(points, 100, 100), 100, 100), DefaultConfig())
+	assert.False(t, ok)
+}
+
+func TestHandOnHip(t *testing.T) {
+	cfg := DefaultConfig()
+	// wrist below the hip by distance in torso length of 30, the elbow stays bent below HandsOnHipsElbowAngle
+	for _, tt := range []struct {
+		distance float64
+		want     float64
+	}{
+		{0.2, 1},
+		{0.3, 1 - (0.3-0.35+0.105)/0.21},
+		{0.35, 0.5},
+		{0.4, 1 - (0.4-0.35+0.105)/0.21},
+		{0.5, 0},
+	} {
+		points := standingLegs()
+		points[openpose.CocoPartRElbow] = openpose.Pt(25, 40)
+		points[openpose.CocoPartRWrist] = openpose.Pt(45, 50+tt.distance*30)
+		score, ok := handOnHip(NewFeatures(posetest.Human(points, 100, 100), 100, 100), cfg, openpose.SideRight)
+		assert.True(t, ok)
+		assert.InDelta(t, tt.want, score, 1e-6, "distance %v", tt.distance)
+	}
+	// wrist on the hip, the upper arm hangs down and the forearm is turned by angle
+	for _, tt := range []struct {
+		angle float64
+		want  float64
+	}{
+		{100, 1},
+		{125, 0.75},
+		{130, 0.5},
+		{135, 0.25},
+		{150, 0},
+	} {
+		rad := tt.angle * math.Pi / 180
+		wrist := openpose.Pt(42+15*math.Sin(rad), 35-15*math.Cos(rad))
+		points := standingLegs()
+		points[openpose.CocoPartRElbow] = openpose.Pt(42, 35)
+		points[openpose.CocoPartRWrist] = wrist
+		points[openpose.CocoPartRHip] = wrist
+		score, ok := handOnHip(NewFeatures(posetest.Human(points, 100, 100), 100, 100), cfg, openpose.SideRight)
+		assert.True(t, ok)
+		assert.InDelta(t, tt.want, score, 1e-6, "angle %v", tt.angle)
+	}
+	// missing elbow
+	points := standingLegs()
+	points[openpose.CocoPartRWrist] = openpose.Pt(45, 50)
+	_, ok := handOnHip(NewFeatures(posetest.Human(points, 100, 100), 100, 100), cfg, openpose.SideRight)
+	assert.False(t, ok)
+}
+
+func TestRamp(t *testing.T) {
+	assert.Equal(t, 0.0, rampUp(80, 100, 10))
+	assert.Equal(t, 0.0, rampUp(90, 100, 10))
+	assert.Equal(t, 0.25, rampUp(95, 100, 10))
+	assert.Equal(t, 0.5, rampUp(100, 100, 10))
+	assert.Equal(t, 1.0, rampUp(110, 100, 10))
+	assert.Equal(t, 0.75, rampDown(95, 100, 10))
+	// without softness the ramp is a step at threshold
+	assert.Equal(t, 1.0, rampUp(100, 100, 0))
+	assert.Equal(t, 0.0, rampUp(99.9, 100, 0))
+}
@@ -0,0 +1,79 @@
+package posture
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// Config represents tunable thresholds of builtin rules, angles are in degrees
+// and distances are ratios of torso length
+type Config struct {
+	// MinConfidence labels below this confidence are dropped
+	MinConfidence float64 `json:"min_confidence"`
+	// UprightTrunkInclination max trunk inclination to vertical of an upright body
+	UprightTrunkInclination float64 `json:"upright_trunk_inclination"`
+	// LyingTrunkInclination min trunk inclination to vertical of a lying body
+	LyingTrunkInclination float64 `json:"lying_trunk_inclination"`
+	// StandingKneeAngle min knee angle of straight legs
+	StandingKneeAngle float64 `json:"standing_knee_angle"`
+	// StandingHipAngle min hip angle of straight hips
+	StandingHipAngle float64 `json:"standing_hip_angle"`
+	// SittingHipAngle max hip angle of a sitting body
+	SittingHipAngle float64 `json:"sitting_hip_angle"`
+	// CrouchingKneeAngle max knee angle of a crouching body
+	CrouchingKneeAngle float64 `json:"crouching_knee_angle"`
+	// CrouchingHipHeight max height of mid hip above mid ankle of a crouching body
+	CrouchingHipHeight float64 `json:"crouching_hip_height"`
+	// ArmRaisedMargin min distance of wrist above shoulder
+	ArmRaisedMargin float64 `json:"arm_raised_margin"`
+	// HandsOnHipsDistance max distance of wrist to hip
+	HandsOnHipsDistance float64 `json:"hands_on_hips_distance"`
+	// HandsOnHipsElbowAngle max elbow angle of hands on hips
+	HandsOnHipsElbowAngle float64 `json:"hands_on_hips_elbow_angle"`
+	// TPoseShoulderTolerance max deviation of shoulder angle from 90
+	TPoseShoulderTolerance float64 `json:"t_pose_shoulder_tolerance"`
+	// TPoseElbowAngle min elbow angle of straight arms
+	TPoseElbowAngle float64 `json:"t_pose_elbow_angle"`
+	// Softness width of the linear ramp around angle thresholds
+	Softness float64 `json:"softness"`
+}
+
+// DefaultConfig returns default config of builtin rules
+func DefaultConfig() Config {
+	return Config{
+		MinConfidence:           0.5,
+		UprightTrunkInclination: 30,
+		LyingTrunkInclination:   60,
+		StandingKneeAngle:       155,
+		StandingHipAngle:        150,
+		SittingHipAngle:         130,
+		CrouchingKneeAngle:      100,
+		CrouchingHipHeight:      0.6,
+		ArmRaisedMargin:         0.1,
+		HandsOnHipsDistance:     0.35,
+		HandsOnHipsElbowAngle:   130,
+		TPoseShoulderTolerance:  20,
+		TPoseElbowAngle:         150,
+		Softness:                10,
+	}
+}
+
+// LoadConfig decodes json config, missing fields keep default values
+func LoadConfig(r io.Reader) (Config, error) {
+	cfg := DefaultConfig()
+	if err := json.NewDecoder(r).Decode(&cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// LoadConfigFile loads json config file
+func LoadConfigFile(filePath string) (Config, error) {
+	fn, err := os.Open(filePath)
+	if err != nil {
+		return DefaultConfig(), err
+	}
+	defer fn.Close()
+	return LoadConfig(fn)
+}
@@ -0,0 +1,2 @@
+// Package posture rule based posture classifier
+package posture
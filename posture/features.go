@@ -0,0 +1,87 @@
+package posture
+
+import (
+	"math"
+
+	"github.com/bububa/openpose"
+)
+
+// Features represents posture features of a human in pixel space
+type Features struct {
+	// Human source human
+	Human openpose.Human
+	// Kinematics joint angles and limb lengths
+	Kinematics openpose.Kinematics
+	// TorsoLength neck to mid hip length in pixels, 0 if not computable
+	TorsoLength float64
+	imgW        float64
+	imgH        float64
+}
+
+// NewFeatures returns Features of human compared to img size (w, h)
+func NewFeatures(h openpose.Human, imgW float64, imgH float64) *Features {
+	f := &Features{
+		Human:      h,
+		Kinematics: h.Kinematics(imgW, imgH),
+		imgW:       imgW,
+		imgH:       imgH,
+	}
+	if f.Kinematics.TorsoLength.Valid {
+		f.TorsoLength = f.Kinematics.TorsoLength.Value
+	} else if shoulders := f.Kinematics.Lengths[openpose.LimbShoulders]; shoulders.Valid {
+		// torso is about 1.5 times of shoulder width
+		f.TorsoLength = shoulders.Value * 1.5
+	}
+	return f
+}
+
+// Point returns pixel point of part if visible
+func (f *Features) Point(part openpose.CocoPart) (openpose.Point, bool) {
+	bodyPart, found := f.Human.GetPart(part, openpose.ThresholdPartConfidence)
+	if !found {
+		return openpose.ZP, false
+	}
+	return bodyPart.Point.Scale(f.imgW, f.imgH), true
+}
+
+// Angle returns average angle of computable joints
+func (f *Features) Angle(joints ...openpose.CocoPart) (float64, bool) {
+	var (
+		sum   float64
+		count float64
+	)
+	for _, joint := range joints {
+		if m := f.Kinematics.Angles[joint]; m.Valid {
+			sum += m.Value
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return sum / count, true
+}
+
+// Relative returns distance in torso length, false if torso length is unknown
+func (f *Features) Relative(distance float64) (float64, bool) {
+	if f.TorsoLength <= 1e-15 {
+		return 0, false
+	}
+	return distance / f.TorsoLength, true
+}
+
+// rampUp returns 0 below threshold-softness, 1 above threshold+softness and linear in between
+func rampUp(value float64, threshold float64, softness float64) float64 {
+	if softness <= 1e-15 {
+		if value >= threshold {
+			return 1
+		}
+		return 0
+	}
+	return math.Max(0, math.Min(1, (value-threshold+softness)/(2*softness)))
+}
+
+// rampDown returns 1 below threshold-softness, 0 above threshold+softness and linear in between
+func rampDown(value float64, threshold float64, softness float64) float64 {
+	return 1 - rampUp(value, threshold, softness)
+}
@@ -0,0 +1,206 @@
+package posture
+
+import (
+	"math"
+
+	"github.com/bububa/openpose"
+)
+
+// Rule represents a posture rule, Score returns confidence in [0, 1] and false if not computable
+type Rule struct {
+	Posture Posture
+	Score   func(f *Features, cfg Config) (float64, bool)
+}
+
+// BuiltinRules represents builtin posture rules
+var BuiltinRules = []Rule{
+	{Posture: Standing, Score: scoreStanding},
+	{Posture: Sitting, Score: scoreSitting},
+	{Posture: Lying, Score: scoreLying},
+	{Posture: Crouching, Score: scoreCrouching},
+	{Posture: RightArmRaised, Score: scoreRightArmRaised},
+	{Posture: LeftArmRaised, Score: scoreLeftArmRaised},
+	{Posture: BothArmsRaised, Score: scoreBothArmsRaised},
+	{Posture: HandsOnHips, Score: scoreHandsOnHips},
+	{Posture: TPose, Score: scoreTPose},
+}
+
+func scoreUpright(f *Features, cfg Config) (float64, bool) {
+	trunk := f.Kinematics.TrunkInclination
+	if !trunk.Valid {
+		return 0, false
+	}
+	return rampDown(trunk.Value, cfg.UprightTrunkInclination, cfg.Softness), true
+}
+
+// hipHeight returns vertical distance from mid hip to mid ankle in torso length
+func hipHeight(f *Features) (float64, bool) {
+	hip, foundHip := f.Human.PixelCenter([]openpose.CocoPart{openpose.CocoPartRHip, openpose.CocoPartLHip}, f.imgW, f.imgH)
+	ankle, foundAnkle := f.Human.PixelCenter([]openpose.CocoPart{openpose.CocoPartRAnkle, openpose.CocoPartLAnkle}, f.imgW, f.imgH)
+	if !foundHip || !foundAnkle {
+		return 0, false
+	}
+	return f.Relative(ankle.Y - hip.Y)
+}
+
+func scoreStanding(f *Features, cfg Config) (float64, bool) {
+	upright, ok := scoreUpright(f, cfg)
+	if !ok {
+		return 0, false
+	}
+	knee, ok := f.Angle(openpose.CocoPartRKnee, openpose.CocoPartLKnee)
+	if !ok {
+		return 0, false
+	}
+	score := upright * rampUp(knee, cfg.StandingKneeAngle, cfg.Softness)
+	if hip, ok := f.Angle(openpose.CocoPartRHip, openpose.CocoPartLHip); ok {
+		score *= rampUp(hip, cfg.StandingHipAngle, cfg.Softness)
+	}
+	return score, true
+}
+
+func scoreSitting(f *Features, cfg Config) (float64, bool) {
+	upright, ok := scoreUpright(f, cfg)
+	if !ok {
+		return 0, false
+	}
+	hip, ok := f.Angle(openpose.CocoPartRHip, openpose.CocoPartLHip)
+	if !ok {
+		return 0, false
+	}
+	score := upright * rampDown(hip, cfg.SittingHipAngle, cfg.Softness)
+	if knee, ok := f.Angle(openpose.CocoPartRKnee, openpose.CocoPartLKnee); ok {
+		score *= rampDown(knee, cfg.StandingKneeAngle, cfg.Softness)
+	}
+	// hips of a sitting body stay high above the ankles, otherwise it's crouching
+	if height, ok := hipHeight(f); ok {
+		score *= rampUp(height, cfg.CrouchingHipHeight, 0.15)
+	}
+	return score, true
+}
+
+func scoreLying(f *Features, cfg Config) (float64, bool) {
+	trunk := f.Kinematics.TrunkInclination
+	if !trunk.Valid {
+		return 0, false
+	}
+	return rampUp(trunk.Value, cfg.LyingTrunkInclination, cfg.Softness), true
+}
+
+func scoreCrouching(f *Features, cfg Config) (float64, bool) {
+	knee, ok := f.Angle(openpose.CocoPartRKnee, openpose.CocoPartLKnee)
+	if !ok {
+		return 0, false
+	}
+	score := rampDown(knee, cfg.CrouchingKneeAngle, cfg.Softness)
+	if height, ok := hipHeight(f); ok {
+		score *= rampDown(height, cfg.CrouchingHipHeight, 0.15)
+	}
+	if trunk := f.Kinematics.TrunkInclination; trunk.Valid {
+		score *= rampDown(trunk.Value, cfg.LyingTrunkInclination, cfg.Softness)
+	}
+	return score, true
+}
+
+// armRaised returns how much wrist is raised above shoulder
+func armRaised(f *Features, cfg Config, side openpose.Side) (float64, bool) {
+	shoulderPart, wristPart := openpose.CocoPartRShoulder, openpose.CocoPartRWrist
+	if side == openpose.SideLeft {
+		shoulderPart, wristPart = openpose.CocoPartLShoulder, openpose.CocoPartLWrist
+	}
+	shoulder, foundShoulder := f.Point(shoulderPart)
+	wrist, foundWrist := f.Point(wristPart)
+	if !foundShoulder || !foundWrist {
+		return 0, false
+	}
+	margin, ok := f.Relative(shoulder.Y - wrist.Y)
+	if !ok {
+		return 0, false
+	}
+	return rampUp(margin, cfg.ArmRaisedMargin, cfg.ArmRaisedMargin), true
+}
+
+func scoreRightArmRaised(f *Features, cfg Config) (float64, bool) {
+	right, ok := armRaised(f, cfg, openpose.SideRight)
+	if !ok {
+		return 0, false
+	}
+	left, _ := armRaised(f, cfg, openpose.SideLeft)
+	return right * (1 - left), true
+}
+
+func scoreLeftArmRaised(f *Features, cfg Config) (float64, bool) {
+	left, ok := armRaised(f, cfg, openpose.SideLeft)
+	if !ok {
+		return 0, false
+	}
+	right, _ := armRaised(f, cfg, openpose.SideRight)
+	return left * (1 - right), true
+}
+
+func scoreBothArmsRaised(f *Features, cfg Config) (float64, bool) {
+	right, okRight := armRaised(f, cfg, openpose.SideRight)
+	left, okLeft := armRaised(f, cfg, openpose.SideLeft)
+	if !okRight || !okLeft {
+		return 0, false
+	}
+	return right * left, true
+}
+
+func handOnHip(f *Features, cfg Config, side openpose.Side) (float64, bool) {
+	elbowPart, wristPart, hipPart := openpose.CocoPartRElbow, openpose.CocoPartRWrist, openpose.CocoPartRHip
+	if side == openpose.SideLeft {
+		elbowPart, wristPart, hipPart = openpose.CocoPartLElbow, openpose.CocoPartLWrist, openpose.CocoPartLHip
+	}
+	wrist, foundWrist := f.Point(wristPart)
+	hip, foundHip := f.Point(hipPart)
+	elbow, foundElbow := f.Angle(elbowPart)
+	if !foundWrist || !foundHip || !foundElbow {
+		return 0, false
+	}
+	distance, ok := f.Relative(wrist.Distance(hip))
+	if !ok {
+		return 0, false
+	}
+	score := rampDown(distance, cfg.HandsOnHipsDistance, cfg.HandsOnHipsDistance*0.3)
+	return score * rampDown(elbow, cfg.HandsOnHipsElbowAngle, cfg.Softness), true
+}
+
+func scoreHandsOnHips(f *Features, cfg Config) (float64, bool) {
+	right, okRight := handOnHip(f, cfg, openpose.SideRight)
+	left, okLeft := handOnHip(f, cfg, openpose.SideLeft)
+	if !okRight || !okLeft {
+		return 0, false
+	}
+	return right * left, true
+}
+
+func armStretched(f *Features, cfg Config, side openpose.Side) (float64, bool) {
+	shoulderPart, elbowPart, wristPart := openpose.CocoPartRShoulder, openpose.CocoPartRElbow, openpose.CocoPartRWrist
+	if side == openpose.SideLeft {
+		shoulderPart, elbowPart, wristPart = openpose.CocoPartLShoulder, openpose.CocoPartLElbow, openpose.CocoPartLWrist
+	}
+	shoulderAngle, foundShoulder := f.Angle(shoulderPart)
+	elbowAngle, foundElbow := f.Angle(elbowPart)
+	shoulder, _ := f.Point(shoulderPart)
+	wrist, foundWrist := f.Point(wristPart)
+	if !foundShoulder || !foundElbow || !foundWrist {
+		return 0, false
+	}
+	height, ok := f.Relative(math.Abs(wrist.Y - shoulder.Y))
+	if !ok {
+		return 0, false
+	}
+	score := rampDown(math.Abs(shoulderAngle-90), cfg.TPoseShoulderTolerance, cfg.Softness)
+	score *= rampUp(elbowAngle, cfg.TPoseElbowAngle, cfg.Softness)
+	return score * rampDown(height, 0.3, 0.1), true
+}
+
+func scoreTPose(f *Features, cfg Config) (float64, bool) {
+	right, okRight := armStretched(f, cfg, openpose.SideRight)
+	left, okLeft := armStretched(f, cfg, openpose.SideLeft)
+	if !okRight || !okLeft {
+		return 0, false
+	}
+	return right * left, true
+}
@@ -0,0 +1,2 @@
+// Package proximity measures floor distances between people and alerts on close contacts
+package proximity
@@ -0,0 +1,96 @@
+package proximity
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+	"github.com/llgcode/draw2d/draw2dkit"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/bububa/openpose"
+)
+
+var (
+	// ContactColor line color of pairs within radius
+	ContactColor = color.RGBA{255, 0, 0, 255}
+	// NearColor line color of pairs within twice the radius
+	NearColor = color.RGBA{255, 170, 0, 255}
+	// TextColor distance label color
+	TextColor = color.RGBA{255, 255, 255, 255}
+)
+
+// DrawDistances draws ground points of people and distance lines of pairs within twice the radius on img
+func DrawDistances(img image.Image, people []Person, pairs []Pair, radius float64, strokeWidth float64) image.Image {
+	bounds := img.Bounds()
+	// draw2d rasterizes from origin, draw at origin and move to img bounds at last
+	out := image.NewRGBA(bounds.Sub(bounds.Min))
+	draw.Draw(out, out.Bounds(), img, bounds.Min, draw.Src)
+	gc := draw2dimg.NewGraphicContext(out)
+	positions := make(map[int]openpose.Point, len(people))
+	for _, p := range people {
+		positions[p.ID] = p.Position.Image
+	}
+	type label struct {
+		at   openpose.Point
+		text string
+		bg   color.Color
+	}
+	var labels []label
+	for _, pair := range pairs {
+		if pair.Distance > 2*radius {
+			continue
+		}
+		a, foundA := positions[pair.A]
+		b, foundB := positions[pair.B]
+		if !foundA || !foundB {
+			continue
+		}
+		lineColor := NearColor
+		if pair.Distance <= radius {
+			lineColor = ContactColor
+		}
+		gc.SetStrokeColor(lineColor)
+		gc.SetLineWidth(strokeWidth)
+		gc.BeginPath()
+		gc.MoveTo(a.X, a.Y)
+		gc.LineTo(b.X, b.Y)
+		gc.Stroke()
+		labels = append(labels, label{
+			at:   a.Add(b).Mul(0.5),
+			text: fmt.Sprintf("%.1f", pair.Distance),
+			bg:   lineColor,
+		})
+	}
+	for _, p := range positions {
+		gc.SetFillColor(TextColor)
+		draw2dkit.Circle(gc, p.X, p.Y, strokeWidth*1.5)
+		gc.Fill()
+	}
+	for _, l := range labels {
+		drawText(out, int(l.at.X), int(l.at.Y), l.text, TextColor, l.bg)
+	}
+	out.Rect = bounds
+	return out
+}
+
+// drawText draws text with background centered at (x, y)
+func drawText(img draw.Image, x int, y int, text string, fg color.Color, bg color.Color) {
+	face := basicfont.Face7x13
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(fg),
+		Face: face,
+	}
+	width := drawer.MeasureString(text).Ceil()
+	x -= width / 2
+	y += (face.Ascent - face.Descent) / 2
+	bounds := image.Rect(x-2, y-face.Ascent-2, x+width+2, y+face.Descent+2)
+	draw.Draw(img, bounds.Intersect(img.Bounds()), image.NewUniform(bg), image.Point{}, draw.Src)
+	drawer.Dot = fixed.P(x, y)
+	drawer.DrawString(text)
+}
@@ -0,0 +1,262 @@
+package proximity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bububa/openpose/ground"
+)
+
+// Person represents a tracked person with its position
+type Person struct {
+	// ID track id
+	ID int
+	// Position image and floor position
+	Position ground.Position
+}
+
+// Pair represents floor distance of two people
+type Pair struct {
+	// A track id of the first person, less than B
+	A int
+	// B track id of the second person
+	B int
+	// Distance floor distance in floor plan units
+	Distance float64
+}
+
+// Distances returns floor distances of all pairs of people, ordered by distance
+func Distances(people []Person) []Pair {
+	ret := make([]Pair, 0, len(people)*(len(people)-1)/2)
+	for i := 0; i < len(people); i++ {
+		for j := i + 1; j < len(people); j++ {
+			a, b := people[i], people[j]
+			if a.ID > b.ID {
+				a, b = b, a
+			}
+			ret = append(ret, Pair{
+				A:        a.ID,
+				B:        b.ID,
+				Distance: a.Position.Floor.Distance(b.Position.Floor),
+			})
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Distance < ret[j].Distance
+	})
+	return ret
+}
+
+// Config represents proximity monitor params
+type Config struct {
+	// Radius people closer than radius in floor plan units are in contact
+	Radius float64
+	// Dwell contact duration to raise an alert
+	Dwell time.Duration
+	// MaxGap a contact interrupted no longer than MaxGap continues
+	MaxGap time.Duration
+}
+
+// DefaultConfig returns default proximity monitor params of floor plan in metres
+func DefaultConfig() Config {
+	return Config{
+		Radius: 1.5,
+		Dwell:  5 * time.Second,
+		MaxGap: time.Second,
+	}
+}
+
+// EventKind represents kind of proximity event
+type EventKind int
+
+const (
+	// Alert people stayed close longer than dwell time
+	Alert EventKind = iota
+	// Clear people of an alert moved apart
+	Clear
+)
+
+// String returns EventKind name
+func (k EventKind) String() string {
+	if k == Clear {
+		return "clear"
+	}
+	return "alert"
+}
+
+// Event represents a proximity event of a group of people
+type Event struct {
+	// Kind event kind
+	Kind EventKind
+	// People track ids of people in the group, ascending
+	People []int
+	// Durations contact duration of each person in the group
+	Durations map[int]time.Duration
+	// Time event time
+	Time time.Time
+}
+
+// String returns event description
+func (e Event) String() string {
+	parts := make([]string, 0, len(e.People))
+	for _, id := range e.People {
+		parts = append(parts, fmt.Sprintf("#%d %s", id, e.Durations[id].Round(100*time.Millisecond)))
+	}
+	return fmt.Sprintf("%s %s", e.Kind, strings.Join(parts, ", "))
+}
+
+// contact represents contact state of a pair
+type contact struct {
+	since time.Time
+	last  time.Time
+}
+
+// pairKey represents ids of a pair, a < b
+type pairKey [2]int
+
+// group represents an alerted group
+type group struct {
+	people    []int
+	durations map[int]time.Duration
+}
+
+// Monitor tracks contacts between people and raises alerts on long ones
+type Monitor struct {
+	cfg      Config
+	contacts map[pairKey]*contact
+	groups   map[string]group
+	mutex    sync.Mutex
+}
+
+// NewMonitor returns a new Monitor
+func NewMonitor(cfg Config) *Monitor {
+	return &Monitor{
+		cfg:      cfg,
+		contacts: make(map[pairKey]*contact),
+		groups:   make(map[string]group),
+	}
+}
+
+// Radius returns contact radius in floor plan units
+func (m *Monitor) Radius() float64 {
+	return m.cfg.Radius
+}
+
+// Update updates contacts of people at t and returns pairwise distances and events.
+// People linked by contacts longer than dwell time form a group, an Alert is raised when
+// a group forms or its members change, and a Clear when it breaks up
+func (m *Monitor) Update(people []Person, t time.Time) ([]Pair, []Event) {
+	pairs := Distances(people)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, pair := range pairs {
+		if pair.Distance > m.cfg.Radius {
+			continue
+		}
+		key := pairKey{pair.A, pair.B}
+		c, found := m.contacts[key]
+		if !found || t.Sub(c.last) > m.cfg.MaxGap {
+			c = &contact{since: t}
+			m.contacts[key] = c
+		}
+		c.last = t
+	}
+	// dwelling contacts linking people into groups
+	parent := make(map[int]int)
+	var find func(int) int
+	find = func(id int) int {
+		if p, found := parent[id]; found && p != id {
+			parent[id] = find(p)
+			return parent[id]
+		}
+		parent[id] = id
+		return id
+	}
+	durations := make(map[int]time.Duration)
+	for key, c := range m.contacts {
+		if t.Sub(c.last) > m.cfg.MaxGap {
+			delete(m.contacts, key)
+			continue
+		}
+		duration := c.last.Sub(c.since)
+		if duration < m.cfg.Dwell {
+			continue
+		}
+		parent[find(key[0])] = find(key[1])
+		for _, id := range key {
+			if durations[id] < duration {
+				durations[id] = duration
+			}
+		}
+	}
+	members := make(map[int][]int)
+	for id := range parent {
+		root := find(id)
+		members[root] = append(members[root], id)
+	}
+	groups := make(map[string]group, len(members))
+	for _, people := range members {
+		sort.Ints(people)
+		g := group{
+			people:    people,
+			durations: make(map[int]time.Duration, len(people)),
+		}
+		for _, id := range people {
+			g.durations[id] = durations[id]
+		}
+		groups[groupKey(people)] = g
+	}
+	var events []Event
+	for key, g := range m.groups {
+		if _, found := groups[key]; !found {
+			events = append(events, newEvent(Clear, g, t))
+		}
+	}
+	for key, g := range groups {
+		if _, found := m.groups[key]; !found {
+			events = append(events, newEvent(Alert, g, t))
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].Kind != events[j].Kind {
+			return events[i].Kind > events[j].Kind
+		}
+		return events[i].People[0] < events[j].People[0]
+	})
+	m.groups = groups
+	return pairs, events
+}
+
+// Forget drops contacts of track
+func (m *Monitor) Forget(trackID int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for key := range m.contacts {
+		if key[0] == trackID || key[1] == trackID {
+			delete(m.contacts, key)
+		}
+	}
+}
+
+func newEvent(kind EventKind, g group, t time.Time) Event {
+	return Event{
+		Kind:      kind,
+		People:    g.people,
+		Durations: g.durations,
+		Time:      t,
+	}
+}
+
+func groupKey(people []int) string {
+	var b strings.Builder
+	for idx, id := range people {
+		if idx > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%d", id)
+	}
+	return b.String()
+}
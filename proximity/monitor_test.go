@@ -0,0 +1,76 @@
+package proximity
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/ground"
+)
+
+func person(id int, x float64, y float64) Person {
+	return Person{
+		ID: id,
+		Position: ground.Position{
+			Image: openpose.Pt(x*100, y*100),
+			Floor: openpose.Pt(x, y),
+		},
+	}
+}
+
+func TestDistances(t *testing.T) {
+	pairs := Distances([]Person{person(3, 0, 0), person(1, 3, 4), person(2, 0, 1)})
+	if assert.Len(t, pairs, 3) {
+		assert.Equal(t, Pair{A: 2, B: 3, Distance: 1}, pairs[0])
+		assert.Equal(t, 1, pairs[2].A)
+		assert.Equal(t, 3, pairs[2].B)
+		assert.InDelta(t, 5, pairs[2].Distance, 1e-9)
+	}
+}
+
+func TestMonitor_Update(t *testing.T) {
+	cfg := DefaultConfig()
+	m := NewMonitor(cfg)
+	start := time.Now()
+	var events []Event
+	for frame := 0; frame <= 100; frame++ {
+		ts := start.Add(time.Duration(frame) * 100 * time.Millisecond)
+		// 1 and 2 stand together for 8s, 3 joins after 2s then the group splits
+		people := []Person{person(1, 0, 0), person(2, 1, 0), person(3, 2, 0)}
+		if frame < 20 {
+			people[2] = person(3, 6, 0)
+		}
+		if frame > 80 {
+			people[1] = person(2, 5, 0)
+			people[2] = person(3, 9, 0)
+		}
+		_, evs := m.Update(people, ts)
+		events = append(events, evs...)
+	}
+	if assert.Len(t, events, 4) {
+		assert.Equal(t, Alert, events[0].Kind)
+		assert.Equal(t, []int{1, 2}, events[0].People)
+		assert.InDelta(t, cfg.Dwell.Seconds(), events[0].Durations[1].Seconds(), 0.01)
+		assert.Equal(t, Clear, events[1].Kind)
+		assert.Equal(t, Alert, events[2].Kind)
+		assert.Equal(t, []int{1, 2, 3}, events[2].People)
+		assert.InDelta(t, 7, events[2].Durations[2].Seconds(), 0.01)
+		assert.InDelta(t, 5, events[2].Durations[3].Seconds(), 0.01)
+		// cleared once the contacts are interrupted longer than max gap
+		assert.Equal(t, Clear, events[3].Kind)
+		assert.Equal(t, []int{1, 2, 3}, events[3].People)
+		assert.InDelta(t, 8.1+cfg.MaxGap.Seconds(), events[3].Time.Sub(start).Seconds(), 0.15)
+	}
+}
+
+func TestDrawDistances(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 640, 480))
+	people := []Person{person(1, 1, 1), person(2, 2, 1)}
+	out := DrawDistances(img, people, Distances(people), 1.5, 3)
+	assert.Equal(t, img.Bounds(), out.Bounds())
+	r, _, _, _ := out.At(125, 100).RGBA()
+	assert.Equal(t, uint32(0xffff), r)
+}
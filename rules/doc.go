@@ -0,0 +1,11 @@
+// Package rules declarative pose condition rule engine.
+//
+// A rule is a boolean expression over a Human, optionally held for a duration:
+//
+//	angle(RElbow) < 60 and y(RWrist) < y(Nose) for 500ms
+//
+// Numbers: x(Part), y(Part) and score(Part) of a part in pixels, angle(Joint) joint angle in degrees,
+// dist(Part, Part) distance in pixels, trunk() trunk inclination in degrees, torso() torso length in pixels,
+// abs(n), min(n, n) and max(n, n). Booleans: visible(Part), comparisons, and, or, not.
+// Conditions referencing missing parts are false.
+package rules
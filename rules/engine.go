@@ -0,0 +1,193 @@
+package rules
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bububa/openpose"
+)
+
+// Rule represents a compiled pose condition rule
+type Rule struct {
+	// Name event name emitted when the rule matches
+	Name string
+	// Source rule expression
+	Source string
+	// Duration the condition must hold before the event is emitted
+	Duration time.Duration
+	cond     boolFunc
+}
+
+// Compile compiles rule expression source into a Rule
+func Compile(name string, source string) (*Rule, error) {
+	cond, duration, err := parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("rule %s: %w", name, err)
+	}
+	return &Rule{
+		Name:     name,
+		Source:   source,
+		Duration: duration,
+		cond:     cond,
+	}, nil
+}
+
+// MustCompile is like Compile but panics if the source cannot be compiled
+func MustCompile(name string, source string) *Rule {
+	rule, err := Compile(name, source)
+	if err != nil {
+		panic(err)
+	}
+	return rule
+}
+
+// Match returns the condition of rule holds on frame, without the duration
+func (r *Rule) Match(f *Frame) bool {
+	v, ok := r.cond(f)
+	return ok && v
+}
+
+// ParseRules parses rules, one "name: expression" per line. Blank lines and lines starting with # are skipped
+func ParseRules(r io.Reader) ([]*Rule, error) {
+	var (
+		rules   []*Rule
+		scanner = bufio.NewScanner(r)
+		lineNo  int
+	)
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		idx := strings.Index(line, ":")
+		if idx <= 0 {
+			return nil, fmt.Errorf("line %d: expected name: expression", lineNo)
+		}
+		rule, err := Compile(strings.TrimSpace(line[:idx]), strings.TrimSpace(line[idx+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, scanner.Err()
+}
+
+// LoadRulesFile loads rules file
+func LoadRulesFile(filePath string) ([]*Rule, error) {
+	fn, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer fn.Close()
+	return ParseRules(fn)
+}
+
+// Event represents a rule matched for a subject
+type Event struct {
+	// Name rule name
+	Name string
+	// ID subject id, e.g. track id
+	ID int
+	// Time when the event is emitted
+	Time time.Time
+	// Since when the condition started to hold
+	Since time.Time
+}
+
+// String returns Event string representation
+func (e Event) String() string {
+	return fmt.Sprintf("Event:%s id:%d held:%s", e.Name, e.ID, e.Time.Sub(e.Since))
+}
+
+// state represents condition state of a rule for a subject
+type state struct {
+	since   time.Time
+	holding bool
+	emitted bool
+	seen    time.Time
+}
+
+type stateKey struct {
+	id   int
+	rule int
+}
+
+// Engine evaluates rules over time for subjects
+type Engine struct {
+	rules  []*Rule
+	states map[stateKey]*state
+	mutex  sync.Mutex
+}
+
+// NewEngine returns a new Engine with rules
+func NewEngine(rules ...*Rule) *Engine {
+	return &Engine{
+		rules:  rules,
+		states: make(map[stateKey]*state),
+	}
+}
+
+// Rules returns engine rules
+func (e *Engine) Rules() []*Rule {
+	return e.rules
+}
+
+// Process evaluates rules on human of subject id at t, returns events of rules whose condition
+// has just held for their duration. An event is emitted once until the condition stops holding
+func (e *Engine) Process(id int, h openpose.Human, imgW float64, imgH float64, t time.Time) []Event {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	frame := &Frame{Human: h, Width: imgW, Height: imgH}
+	var events []Event
+	for idx, rule := range e.rules {
+		key := stateKey{id: id, rule: idx}
+		st, found := e.states[key]
+		if !found {
+			st = new(state)
+			e.states[key] = st
+		}
+		st.seen = t
+		if !rule.Match(frame) {
+			st.holding = false
+			st.emitted = false
+			continue
+		}
+		if !st.holding {
+			st.holding = true
+			st.since = t
+		}
+		if !st.emitted && t.Sub(st.since) >= rule.Duration {
+			st.emitted = true
+			events = append(events, Event{Name: rule.Name, ID: id, Time: t, Since: st.since})
+		}
+	}
+	return events
+}
+
+// Forget drops states of subject id
+func (e *Engine) Forget(id int) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	for key := range e.states {
+		if key.id == id {
+			delete(e.states, key)
+		}
+	}
+}
+
+// Prune drops states of subjects not processed since t
+func (e *Engine) Prune(t time.Time) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	for key, st := range e.states {
+		if st.seen.Before(t) {
+			delete(e.states, key)
+		}
+	}
+}
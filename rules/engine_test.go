@@ -0,0 +1,112 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/internal/posetest"
+)
+
+func raisedArm() openpose.Human {
+	return posetest.Human(map[openpose.CocoPart]openpose.Point{
+		openpose.CocoPartNose:      openpose.Pt(50, 25),
+		openpose.CocoPartRShoulder: openpose.Pt(40, 30),
+		openpose.CocoPartRElbow:    openpose.Pt(30, 30),
+		openpose.CocoPartRWrist:    openpose.Pt(38, 22),
+	}, 100, 100)
+}
+
+func TestCompile_Match(t *testing.T) {
+	frame := &Frame{Human: raisedArm(), Width: 100, Height: 100}
+	cases := map[string]bool{
+		"angle(RElbow) < 60 and y(RWrist) < y(Nose)": true,
+		"angle(RElbow) > 60 or y(RWrist) > y(Nose)":  false,
+		"not visible(LWrist)":                        true,
+		"y(LWrist) < y(Nose)":                        false,
+		"not (y(LWrist) < y(Nose))":                  false,
+		"dist(RShoulder, RElbow) == 10":              true,
+		"-x(RElbow) + 2 * 20 >= 10 && !false":        true,
+		"abs(x(RWrist) - x(Nose)) > max(5, 10)":      true,
+	}
+	for source, expected := range cases {
+		rule, err := Compile("test", source)
+		if assert.Nil(t, err, source) {
+			assert.Equal(t, expected, rule.Match(frame), source)
+		}
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	for _, source := range []string{
+		"angle(RElbow)",
+		"angle(Foo) < 10",
+		"x(RWrist) < ",
+		"visible(RWrist) < 10",
+		"unknown(RWrist)",
+		"visible(RWrist) for 10",
+		"visible(RWrist) for 2m30",
+		"visible(RWrist) for 2m 30s",
+		"visible(RWrist) ?",
+	} {
+		_, err := Compile("test", source)
+		assert.NotNil(t, err, source)
+	}
+}
+
+func TestCompile_Duration(t *testing.T) {
+	for source, want := range map[string]time.Duration{
+		"visible(RWrist) for 500ms":  500 * time.Millisecond,
+		"visible(RWrist) for 1.5s":   1500 * time.Millisecond,
+		"visible(RWrist) for 2m30s":  2*time.Minute + 30*time.Second,
+		"visible(RWrist) for 1h2m3s": time.Hour + 2*time.Minute + 3*time.Second,
+	} {
+		rule, err := Compile("test", source)
+		if assert.NoError(t, err, source) {
+			assert.Equal(t, want, rule.Duration, source)
+		}
+	}
+}
+
+func TestEngine_EmitsAfterDuration(t *testing.T) {
+	rule := MustCompile("raise", "y(RWrist) < y(Nose) for 500ms")
+	assert.Equal(t, 500*time.Millisecond, rule.Duration)
+	engine := NewEngine(rule)
+	start := time.Now()
+	h := raisedArm()
+	assert.Empty(t, engine.Process(1, h, 100, 100, start))
+	assert.Empty(t, engine.Process(1, h, 100, 100, start.Add(300*time.Millisecond)))
+	events := engine.Process(1, h, 100, 100, start.Add(600*time.Millisecond))
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "raise", events[0].Name)
+		assert.Equal(t, start, events[0].Since)
+	}
+	// emitted once while holding
+	assert.Empty(t, engine.Process(1, h, 100, 100, start.Add(900*time.Millisecond)))
+	// other subjects have their own state
+	assert.Empty(t, engine.Process(2, h, 100, 100, start.Add(900*time.Millisecond)))
+	// re-armed when the condition stops holding
+	assert.Empty(t, engine.Process(1, openpose.Human{}, 100, 100, start.Add(time.Second)))
+	assert.Empty(t, engine.Process(1, h, 100, 100, start.Add(1100*time.Millisecond)))
+	assert.Len(t, engine.Process(1, h, 100, 100, start.Add(1700*time.Millisecond)), 1)
+}
+
+func TestParseRules(t *testing.T) {
+	src := `
+# comment
+raise: y(RWrist) < y(Nose) for 1s
+bend: angle(RElbow) < 60
+`
+	rules, err := ParseRules(strings.NewReader(src))
+	assert.Nil(t, err)
+	if assert.Len(t, rules, 2) {
+		assert.Equal(t, "raise", rules[0].Name)
+		assert.Equal(t, time.Second, rules[0].Duration)
+		assert.Equal(t, "bend", rules[1].Name)
+	}
+	_, err = ParseRules(strings.NewReader("no colon"))
+	assert.NotNil(t, err)
+}
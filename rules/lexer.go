@@ -0,0 +1,102 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind represents lexical token kind
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenDuration
+	tokenIdent
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+// token represents lexical token
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q at %d", t.text, t.pos)
+}
+
+var operators = []string{"<=", ">=", "==", "!=", "&&", "||", "<", ">", "+", "-", "*", "/", "!"}
+
+// tokenize splits source into tokens
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+	pos := 0
+	for pos < len(runes) {
+		r := runes[pos]
+		switch {
+		case unicode.IsSpace(r):
+			pos++
+		case r == '(':
+			tokens = append(tokens, token{tokenLParen, "(", pos})
+			pos++
+		case r == ')':
+			tokens = append(tokens, token{tokenRParen, ")", pos})
+			pos++
+		case r == ',':
+			tokens = append(tokens, token{tokenComma, ",", pos})
+			pos++
+		case unicode.IsDigit(r) || r == '.':
+			start := pos
+			kind := tokenNumber
+			// a number followed by a unit is a duration, e.g. 500ms, and number unit pairs are one duration, e.g. 2m30s
+			for {
+				for pos < len(runes) && (unicode.IsDigit(runes[pos]) || runes[pos] == '.') {
+					pos++
+				}
+				unit := pos
+				for pos < len(runes) && unicode.IsLetter(runes[pos]) {
+					pos++
+				}
+				if pos == unit {
+					break
+				}
+				kind = tokenDuration
+				if pos == len(runes) || !(unicode.IsDigit(runes[pos]) || runes[pos] == '.') {
+					break
+				}
+			}
+			tokens = append(tokens, token{kind, string(runes[start:pos]), start})
+		case unicode.IsLetter(r) || r == '_':
+			start := pos
+			for pos < len(runes) && (unicode.IsLetter(runes[pos]) || unicode.IsDigit(runes[pos]) || runes[pos] == '_') {
+				pos++
+			}
+			tokens = append(tokens, token{tokenIdent, string(runes[start:pos]), start})
+		default:
+			var matched bool
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[pos:]), op) {
+					tokens = append(tokens, token{tokenOperator, op, pos})
+					pos += len([]rune(op))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q at %d", r, pos)
+			}
+		}
+	}
+	tokens = append(tokens, token{tokenEOF, "", len(runes)})
+	return tokens, nil
+}
@@ -0,0 +1,536 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bububa/openpose"
+)
+
+// Frame represents a human to evaluate rules on, Width and Height are the image size in pixels
+type Frame struct {
+	Human  openpose.Human
+	Width  float64
+	Height float64
+}
+
+// point returns pixel point of part if visible
+func (f *Frame) point(part openpose.CocoPart) (openpose.Point, bool) {
+	bodyPart, found := f.Human.GetPart(part, openpose.ThresholdPartConfidence)
+	if !found {
+		return openpose.ZP, false
+	}
+	return bodyPart.Point.Scale(f.Width, f.Height), true
+}
+
+// numberFunc evaluates a number, false if not computable
+type numberFunc func(f *Frame) (float64, bool)
+
+// boolFunc evaluates a boolean, false if not computable
+type boolFunc func(f *Frame) (bool, bool)
+
+type kind int
+
+const (
+	kindNumber kind = iota
+	kindBool
+	kindPart
+)
+
+func (k kind) String() string {
+	switch k {
+	case kindNumber:
+		return "number"
+	case kindBool:
+		return "boolean"
+	}
+	return "part"
+}
+
+// expr represents a compiled expression
+type expr struct {
+	kind    kind
+	number  numberFunc
+	boolean boolFunc
+	part    openpose.CocoPart
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parse compiles source into condition and hold duration
+func parse(source string) (boolFunc, time.Duration, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, 0, err
+	}
+	p := &parser{tokens: tokens}
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, 0, err
+	}
+	if cond.kind != kindBool {
+		return nil, 0, fmt.Errorf("rule must be a boolean expression, got %s", cond.kind)
+	}
+	var duration time.Duration
+	if p.isKeyword("for") {
+		p.next()
+		tok := p.next()
+		if tok.kind != tokenDuration {
+			return nil, 0, fmt.Errorf("expected duration after for, got %s", tok)
+		}
+		if duration, err = time.ParseDuration(tok.text); err != nil {
+			return nil, 0, err
+		}
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, 0, fmt.Errorf("unexpected %s", tok)
+	}
+	return cond.boolean, duration, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) isKeyword(word string) bool {
+	tok := p.peek()
+	return tok.kind == tokenIdent && strings.EqualFold(tok.text, word)
+}
+
+func (p *parser) isOperator(ops ...string) bool {
+	tok := p.peek()
+	if tok.kind != tokenOperator {
+		return false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) expect(kind tokenKind, text string) error {
+	if tok := p.next(); tok.kind != kind {
+		return fmt.Errorf("expected %s, got %s", text, tok)
+	}
+	return nil
+}
+
+func expectKind(e expr, k kind, context string) error {
+	if e.kind != k {
+		return fmt.Errorf("%s expects %s, got %s", context, k, e.kind)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return left, err
+	}
+	for p.isKeyword("or") || p.isOperator("||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return right, err
+		}
+		if err := expectKind(left, kindBool, "or"); err != nil {
+			return left, err
+		}
+		if err := expectKind(right, kindBool, "or"); err != nil {
+			return right, err
+		}
+		a, b := left.boolean, right.boolean
+		left = expr{kind: kindBool, boolean: func(f *Frame) (bool, bool) {
+			va, okA := a(f)
+			if okA && va {
+				return true, true
+			}
+			vb, okB := b(f)
+			if okB && vb {
+				return true, true
+			}
+			return false, okA && okB
+		}}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return left, err
+	}
+	for p.isKeyword("and") || p.isOperator("&&") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return right, err
+		}
+		if err := expectKind(left, kindBool, "and"); err != nil {
+			return left, err
+		}
+		if err := expectKind(right, kindBool, "and"); err != nil {
+			return right, err
+		}
+		a, b := left.boolean, right.boolean
+		left = expr{kind: kindBool, boolean: func(f *Frame) (bool, bool) {
+			va, okA := a(f)
+			if okA && !va {
+				return false, true
+			}
+			vb, okB := b(f)
+			if okB && !vb {
+				return false, true
+			}
+			return okA && okB, okA && okB
+		}}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (expr, error) {
+	if p.isKeyword("not") || p.isOperator("!") {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return operand, err
+		}
+		if err := expectKind(operand, kindBool, "not"); err != nil {
+			return operand, err
+		}
+		a := operand.boolean
+		return expr{kind: kindBool, boolean: func(f *Frame) (bool, bool) {
+			v, ok := a(f)
+			return !v, ok
+		}}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (expr, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return left, err
+	}
+	if !p.isOperator("<", "<=", ">", ">=", "==", "!=") {
+		return left, nil
+	}
+	op := p.next().text
+	right, err := p.parseSum()
+	if err != nil {
+		return right, err
+	}
+	if err := expectKind(left, kindNumber, op); err != nil {
+		return left, err
+	}
+	if err := expectKind(right, kindNumber, op); err != nil {
+		return right, err
+	}
+	var compare func(a, b float64) bool
+	switch op {
+	case "<":
+		compare = func(a, b float64) bool { return a < b }
+	case "<=":
+		compare = func(a, b float64) bool { return a <= b }
+	case ">":
+		compare = func(a, b float64) bool { return a > b }
+	case ">=":
+		compare = func(a, b float64) bool { return a >= b }
+	case "==":
+		compare = func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	default:
+		compare = func(a, b float64) bool { return math.Abs(a-b) >= 1e-9 }
+	}
+	a, b := left.number, right.number
+	return expr{kind: kindBool, boolean: func(f *Frame) (bool, bool) {
+		va, okA := a(f)
+		vb, okB := b(f)
+		if !okA || !okB {
+			return false, false
+		}
+		return compare(va, vb), true
+	}}, nil
+}
+
+func (p *parser) parseSum() (expr, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return left, err
+	}
+	for p.isOperator("+", "-") {
+		op := p.next().text
+		right, err := p.parseTerm()
+		if err != nil {
+			return right, err
+		}
+		if left, err = arithmetic(op, left, right); err != nil {
+			return left, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseTerm() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return left, err
+	}
+	for p.isOperator("*", "/") {
+		op := p.next().text
+		right, err := p.parseUnary()
+		if err != nil {
+			return right, err
+		}
+		if left, err = arithmetic(op, left, right); err != nil {
+			return left, err
+		}
+	}
+	return left, nil
+}
+
+func arithmetic(op string, left expr, right expr) (expr, error) {
+	if err := expectKind(left, kindNumber, op); err != nil {
+		return left, err
+	}
+	if err := expectKind(right, kindNumber, op); err != nil {
+		return right, err
+	}
+	a, b := left.number, right.number
+	return expr{kind: kindNumber, number: func(f *Frame) (float64, bool) {
+		va, okA := a(f)
+		vb, okB := b(f)
+		if !okA || !okB {
+			return 0, false
+		}
+		switch op {
+		case "+":
+			return va + vb, true
+		case "-":
+			return va - vb, true
+		case "*":
+			return va * vb, true
+		}
+		if math.Abs(vb) <= 1e-15 {
+			return 0, false
+		}
+		return va / vb, true
+	}}, nil
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	if p.isOperator("-") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return operand, err
+		}
+		if err := expectKind(operand, kindNumber, "-"); err != nil {
+			return operand, err
+		}
+		a := operand.number
+		return expr{kind: kindNumber, number: func(f *Frame) (float64, bool) {
+			v, ok := a(f)
+			return -v, ok
+		}}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return expr{}, fmt.Errorf("invalid number %s", tok)
+		}
+		return expr{kind: kindNumber, number: func(f *Frame) (float64, bool) { return v, true }}, nil
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return inner, err
+		}
+		return inner, p.expect(tokenRParen, ")")
+	case tokenIdent:
+		if p.peek().kind == tokenLParen {
+			p.next()
+			return p.parseCall(tok)
+		}
+		switch strings.ToLower(tok.text) {
+		case "true":
+			return expr{kind: kindBool, boolean: func(f *Frame) (bool, bool) { return true, true }}, nil
+		case "false":
+			return expr{kind: kindBool, boolean: func(f *Frame) (bool, bool) { return false, true }}, nil
+		}
+		part, err := openpose.ParseCocoPart(tok.text)
+		if err != nil || part == openpose.CocoPartBackground {
+			return expr{}, fmt.Errorf("unknown identifier %s", tok)
+		}
+		return expr{kind: kindPart, part: part}, nil
+	}
+	return expr{}, fmt.Errorf("unexpected %s", tok)
+}
+
+func (p *parser) parseCall(name token) (expr, error) {
+	var args []expr
+	if p.peek().kind != tokenRParen {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return arg, err
+			}
+			args = append(args, arg)
+			if p.peek().kind != tokenComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if err := p.expect(tokenRParen, ")"); err != nil {
+		return expr{}, err
+	}
+	fn, found := functions[strings.ToLower(name.text)]
+	if !found {
+		return expr{}, fmt.Errorf("unknown function %s", name)
+	}
+	if len(args) != len(fn.args) {
+		return expr{}, fmt.Errorf("%s expects %d arguments, got %d", name.text, len(fn.args), len(args))
+	}
+	for idx, arg := range args {
+		if err := expectKind(arg, fn.args[idx], name.text); err != nil {
+			return arg, err
+		}
+	}
+	return fn.build(args), nil
+}
+
+// function represents a builtin function
+type function struct {
+	args  []kind
+	build func(args []expr) expr
+}
+
+var functions = map[string]function{
+	"x": {
+		args: []kind{kindPart},
+		build: func(args []expr) expr {
+			part := args[0].part
+			return expr{kind: kindNumber, number: func(f *Frame) (float64, bool) {
+				point, ok := f.point(part)
+				return point.X, ok
+			}}
+		},
+	},
+	"y": {
+		args: []kind{kindPart},
+		build: func(args []expr) expr {
+			part := args[0].part
+			return expr{kind: kindNumber, number: func(f *Frame) (float64, bool) {
+				point, ok := f.point(part)
+				return point.Y, ok
+			}}
+		},
+	},
+	"score": {
+		args: []kind{kindPart},
+		build: func(args []expr) expr {
+			part := args[0].part
+			return expr{kind: kindNumber, number: func(f *Frame) (float64, bool) {
+				bodyPart, found := f.Human.Parts[part]
+				return float64(bodyPart.Score), found
+			}}
+		},
+	},
+	"visible": {
+		args: []kind{kindPart},
+		build: func(args []expr) expr {
+			part := args[0].part
+			return expr{kind: kindBool, boolean: func(f *Frame) (bool, bool) {
+				_, ok := f.point(part)
+				return ok, true
+			}}
+		},
+	},
+	"angle": {
+		args: []kind{kindPart},
+		build: func(args []expr) expr {
+			part := args[0].part
+			return expr{kind: kindNumber, number: func(f *Frame) (float64, bool) {
+				return f.Human.JointAngle(part, f.Width, f.Height)
+			}}
+		},
+	},
+	"dist": {
+		args: []kind{kindPart, kindPart},
+		build: func(args []expr) expr {
+			a, b := args[0].part, args[1].part
+			return expr{kind: kindNumber, number: func(f *Frame) (float64, bool) {
+				pa, okA := f.point(a)
+				pb, okB := f.point(b)
+				return pa.Distance(pb), okA && okB
+			}}
+		},
+	},
+	"trunk": {
+		build: func(args []expr) expr {
+			return expr{kind: kindNumber, number: func(f *Frame) (float64, bool) {
+				return f.Human.TrunkInclination(f.Width, f.Height)
+			}}
+		},
+	},
+	"torso": {
+		build: func(args []expr) expr {
+			return expr{kind: kindNumber, number: func(f *Frame) (float64, bool) {
+				return f.Human.TorsoLength(f.Width, f.Height)
+			}}
+		},
+	},
+	"abs": {
+		args: []kind{kindNumber},
+		build: func(args []expr) expr {
+			a := args[0].number
+			return expr{kind: kindNumber, number: func(f *Frame) (float64, bool) {
+				v, ok := a(f)
+				return math.Abs(v), ok
+			}}
+		},
+	},
+	"min": {
+		args: []kind{kindNumber, kindNumber},
+		build: func(args []expr) expr {
+			a, b := args[0].number, args[1].number
+			return expr{kind: kindNumber, number: func(f *Frame) (float64, bool) {
+				va, okA := a(f)
+				vb, okB := b(f)
+				return math.Min(va, vb), okA && okB
+			}}
+		},
+	},
+	"max": {
+		args: []kind{kindNumber, kindNumber},
+		build: func(args []expr) expr {
+			a, b := args[0].number, args[1].number
+			return expr{kind: kindNumber, number: func(f *Frame) (float64, bool) {
+				va, okA := a(f)
+				vb, okB := b(f)
+				return math.Max(va, vb), okA && okB
+			}}
+		},
+	},
+}
@@ -0,0 +1,2 @@
+// Package scale estimates person height and pixels per metre from skeleton
+package scale
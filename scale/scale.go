@@ -0,0 +1,113 @@
+package scale
+
+import (
+	"math"
+
+	"github.com/bububa/openpose"
+)
+
+// DefaultHeight default adult body height in metres
+const DefaultHeight = 1.7
+
+// Segment represents a body segment with its length ratio to body height
+type Segment struct {
+	// Limbs alternative limbs of the segment, e.g. right and left thigh, the longest visible one is used
+	Limbs []openpose.Limb
+	// Ratio segment length to body height ratio
+	Ratio float64
+}
+
+// Segments represents segments used to estimate body height, ratios are from Drillis and Contini.
+// Nose to neck, neck to hip, thigh and shin chain covers about 0.87 of the body height,
+// the remaining is head top above nose and ankle height. Neck to hip joint is between
+// the side view torso of 0.288 and the front view one with half hip width of 0.303
+var Segments = []Segment{
+	{Limbs: []openpose.Limb{{openpose.CocoPartNeck, openpose.CocoPartNose}}, Ratio: 0.092},
+	{Limbs: []openpose.Limb{{openpose.CocoPartNeck, openpose.CocoPartRHip}, {openpose.CocoPartNeck, openpose.CocoPartLHip}}, Ratio: 0.295},
+	{Limbs: []openpose.Limb{openpose.LimbRThigh, openpose.LimbLThigh}, Ratio: 0.245},
+	{Limbs: []openpose.Limb{openpose.LimbRShin, openpose.LimbLShin}, Ratio: 0.246},
+	{Limbs: []openpose.Limb{openpose.LimbRUpperArm, openpose.LimbLUpperArm}, Ratio: 0.186},
+	{Limbs: []openpose.Limb{openpose.LimbRForearm, openpose.LimbLForearm}, Ratio: 0.146},
+}
+
+// totalRatio sum of all segment ratios
+var totalRatio = func() float64 {
+	var ret float64
+	for _, segment := range Segments {
+		ret += segment.Ratio
+	}
+	return ret
+}()
+
+// Height represents estimated body height
+type Height struct {
+	// Pixels body height in pixels
+	Pixels float64
+	// Coverage ratio sum of the visible segments to all segments in [0, 1]
+	Coverage float64
+}
+
+// EstimateHeight returns body height of human in pixel space compared to img size (w, h) from visible segments.
+// Each visible segment is scaled by its anthropometric ratio, so the head or the feet could be missing.
+// Foreshortened segments underestimate the height, the longest side of each segment is used
+func EstimateHeight(h openpose.Human, imgW float64, imgH float64) (Height, bool) {
+	var length, ratio float64
+	for _, segment := range Segments {
+		var longest float64
+		for _, limb := range segment.Limbs {
+			if l, found := h.LimbLength(limb, imgW, imgH); found {
+				longest = math.Max(longest, l)
+			}
+		}
+		if longest <= 0 {
+			continue
+		}
+		length += longest
+		ratio += segment.Ratio
+	}
+	if ratio == 0 {
+		return Height{}, false
+	}
+	return Height{
+		Pixels:   length / ratio,
+		Coverage: ratio / totalRatio,
+	}, true
+}
+
+// Scaler converts pixels to metres from body height of human
+type Scaler struct {
+	// Height known body height in metres, DefaultHeight if 0
+	Height float64
+	// MinCoverage min segment coverage of a height estimate in [0, 1]
+	MinCoverage float64
+}
+
+// NewScaler returns a new Scaler of known body height in metres, 0 uses DefaultHeight
+func NewScaler(height float64) Scaler {
+	return Scaler{
+		Height:      height,
+		MinCoverage: 0.3,
+	}
+}
+
+// PixelsPerMetre returns pixels per metre at human in pixel space compared to img size (w, h)
+func (s Scaler) PixelsPerMetre(h openpose.Human, imgW float64, imgH float64) (float64, bool) {
+	height, found := EstimateHeight(h, imgW, imgH)
+	if !found || height.Coverage < s.MinCoverage {
+		return 0, false
+	}
+	metres := s.Height
+	if metres <= 0 {
+		metres = DefaultHeight
+	}
+	return height.Pixels / metres, true
+}
+
+// Metres returns pixel distance in metres at human, false if scale is unknown
+func (s Scaler) Metres(h openpose.Human, pixels float64, imgW float64, imgH float64) (float64, bool) {
+	ppm, found := s.PixelsPerMetre(h, imgW, imgH)
+	if !found || ppm <= 0 {
+		return 0, false
+	}
+	return pixels / ppm, true
+}
@@ -0,0 +1,50 @@
+package scale
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/internal/posetest"
+)
+
+// newHuman returns a side view human of 1.75 metres at 200 pixels per metre in a 640x480 image
+func newHuman() openpose.Human {
+	const height = 350.0
+	points := map[openpose.CocoPart]openpose.Point{
+		openpose.CocoPartNose:      openpose.Pt(320, 470-0.91*height),
+		openpose.CocoPartNeck:      openpose.Pt(320, 470-0.818*height),
+		openpose.CocoPartRShoulder: openpose.Pt(320, 470-0.818*height),
+		openpose.CocoPartRElbow:    openpose.Pt(320, 470-0.632*height),
+		openpose.CocoPartRWrist:    openpose.Pt(320, 470-0.486*height),
+		openpose.CocoPartRHip:      openpose.Pt(320, 470-0.53*height),
+		openpose.CocoPartRKnee:     openpose.Pt(320, 470-0.285*height),
+		openpose.CocoPartRAnkle:    openpose.Pt(320, 470-0.039*height),
+	}
+	return posetest.Human(points, 640, 480)
+}
+
+func TestEstimateHeight(t *testing.T) {
+	h := newHuman()
+	height, found := EstimateHeight(h, 640, 480)
+	if assert.True(t, found) {
+		assert.InDelta(t, 350, height.Pixels, 10)
+		assert.InDelta(t, 1, height.Coverage, 1e-9)
+	}
+	// without head and feet
+	for _, part := range []openpose.CocoPart{openpose.CocoPartNose, openpose.CocoPartRAnkle} {
+		delete(h.Parts, part)
+	}
+	height, found = EstimateHeight(h, 640, 480)
+	if assert.True(t, found) {
+		assert.InDelta(t, 350, height.Pixels, 10)
+		assert.Less(t, height.Coverage, 1.0)
+	}
+	ppm, found := NewScaler(1.75).PixelsPerMetre(h, 640, 480)
+	if assert.True(t, found) {
+		assert.InDelta(t, 200, ppm, 6)
+	}
+	_, found = NewScaler(0).PixelsPerMetre(openpose.Human{}, 640, 480)
+	assert.False(t, found)
+}
@@ -0,0 +1,2 @@
+// Package similarity pose similarity metrics
+package similarity
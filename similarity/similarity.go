@@ -0,0 +1,222 @@
+package similarity
+
+import (
+	"math"
+
+	"github.com/bububa/openpose"
+)
+
+// CocoSigmas represents OKS per-part sigmas in CocoPart order.
+// Neck is not annotated in COCO, it uses the shoulder sigma
+var CocoSigmas = [openpose.TotalBodyParts]float64{
+	0.026,               // nose
+	0.079,               // neck
+	0.079, 0.072, 0.062, // right shoulder, elbow, wrist
+	0.079, 0.072, 0.062, // left shoulder, elbow, wrist
+	0.107, 0.087, 0.089, // right hip, knee, ankle
+	0.107, 0.087, 0.089, // left hip, knee, ankle
+	0.025, 0.025, // right eye, left eye
+	0.035, 0.035, // right ear, left ear
+}
+
+// Pose represents translation and scale normalized keypoints
+type Pose struct {
+	// Points normalized keypoints in CocoPart order
+	Points [openpose.TotalBodyParts]openpose.Point
+	// Weights keypoint confidence, 0 means missing
+	Weights [openpose.TotalBodyParts]float64
+}
+
+// Vector returns flatten (x, y) vector of pose, missing parts are zero
+func (p Pose) Vector() []float64 {
+	ret := make([]float64, 0, openpose.TotalBodyParts*2)
+	for _, point := range p.Points {
+		ret = append(ret, point.X, point.Y)
+	}
+	return ret
+}
+
+// Visible returns part is visible in pose or not
+func (p Pose) Visible(part openpose.CocoPart) bool {
+	return p.Weights[part] > 0
+}
+
+// Normalize returns pose of human in pixel space compared to img size (w, h),
+// translated to the centroid of visible parts and scaled to unit RMS distance
+func Normalize(h openpose.Human, imgW float64, imgH float64) Pose {
+	var pose Pose
+	for part := openpose.CocoPartNose; part < openpose.CocoPartBackground; part++ {
+		bodyPart, found := h.GetPart(part, openpose.ThresholdPartConfidence)
+		if !found {
+			continue
+		}
+		pose.Points[part] = bodyPart.Point.Scale(imgW, imgH)
+		pose.Weights[part] = float64(bodyPart.Score)
+	}
+	normalize(&pose, pose.Weights)
+	return pose
+}
+
+// normalize translate and scale pose points in place, only parts with positive mask are used
+func normalize(pose *Pose, mask [openpose.TotalBodyParts]float64) bool {
+	var (
+		center openpose.Point
+		count  float64
+	)
+	for idx, point := range pose.Points {
+		if mask[idx] <= 0 {
+			continue
+		}
+		center = center.Add(point)
+		count++
+	}
+	if count == 0 {
+		return false
+	}
+	center = center.Mul(1 / count)
+	var sum float64
+	for idx, point := range pose.Points {
+		if mask[idx] <= 0 {
+			pose.Points[idx] = openpose.ZP
+			continue
+		}
+		pose.Points[idx] = point.Sub(center)
+		sum += pose.Points[idx].Dot(pose.Points[idx])
+	}
+	scale := math.Sqrt(sum / count)
+	if scale <= 1e-15 {
+		return false
+	}
+	for idx := range pose.Points {
+		pose.Points[idx] = pose.Points[idx].Mul(1 / scale)
+	}
+	return true
+}
+
+// common returns a, b normalized over the parts visible in both and the shared weights
+func common(a openpose.Human, b openpose.Human, imgW float64, imgH float64) (Pose, Pose, [openpose.TotalBodyParts]float64, bool) {
+	pa := Normalize(a, imgW, imgH)
+	pb := Normalize(b, imgW, imgH)
+	var (
+		weights [openpose.TotalBodyParts]float64
+		count   int
+	)
+	for idx := range weights {
+		weights[idx] = math.Min(pa.Weights[idx], pb.Weights[idx])
+		if weights[idx] > 0 {
+			count++
+		}
+	}
+	// at least 2 points are required to normalize scale
+	if count < 2 {
+		return pa, pb, weights, false
+	}
+	if !normalize(&pa, weights) || !normalize(&pb, weights) {
+		return pa, pb, weights, false
+	}
+	return pa, pb, weights, true
+}
+
+// OKS returns Object Keypoint Similarity of dt against gt with CocoSigmas
+func OKS(gt openpose.Human, dt openpose.Human, imgW float64, imgH float64, area float64) float64 {
+	return OKSWithSigmas(gt, dt, imgW, imgH, area, CocoSigmas)
+}
+
+// OKSWithSigmas returns Object Keypoint Similarity of dt against gt in pixel space compared to img size (w, h).
+// area is the gt object area in pixels, gt body box area is used if area <= 0.
+// Every part present in gt is counted, parts missing in dt score 0
+func OKSWithSigmas(gt openpose.Human, dt openpose.Human, imgW float64, imgH float64, area float64, sigmas [openpose.TotalBodyParts]float64) float64 {
+	if area <= 0 {
+		area = float64(gt.GetBodyBox(imgW, imgH, 0, 0).Area())
+	}
+	if area <= 0 {
+		return 0
+	}
+	var (
+		sum   float64
+		count float64
+	)
+	for part, gtPart := range gt.Parts {
+		if part < 0 || part >= openpose.CocoPartBackground {
+			continue
+		}
+		count++
+		dtPart, found := dt.Parts[part]
+		if !found {
+			continue
+		}
+		d := gtPart.Point.Scale(imgW, imgH).Distance(dtPart.Point.Scale(imgW, imgH))
+		k := 2 * sigmas[part]
+		sum += math.Exp(-d * d / (2 * area * k * k))
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / count
+}
+
+// NormalizedDistance returns weighted RMS distance between normalized keypoints visible in both a and b
+func NormalizedDistance(a openpose.Human, b openpose.Human, imgW float64, imgH float64) (float64, bool) {
+	pa, pb, weights, ok := common(a, b, imgW, imgH)
+	if !ok {
+		return 0, false
+	}
+	var sum, total float64
+	for idx, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		d := pa.Points[idx].Sub(pb.Points[idx])
+		sum += w * d.Dot(d)
+		total += w
+	}
+	return math.Sqrt(sum / total), true
+}
+
+// WeightedCosine returns confidence weighted cosine similarity between normalized keypoints visible in both a and b
+func WeightedCosine(a openpose.Human, b openpose.Human, imgW float64, imgH float64) (float64, bool) {
+	pa, pb, weights, ok := common(a, b, imgW, imgH)
+	if !ok {
+		return 0, false
+	}
+	var dot, na, nb float64
+	for idx, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		dot += w * pa.Points[idx].Dot(pb.Points[idx])
+		na += w * pa.Points[idx].Dot(pa.Points[idx])
+		nb += w * pb.Points[idx].Dot(pb.Points[idx])
+	}
+	if na <= 1e-15 || nb <= 1e-15 {
+		return 0, false
+	}
+	return dot / math.Sqrt(na*nb), true
+}
+
+// ProcrustesDistance returns residual distance between a and b after aligning b to a
+// with the optimal rotation, scale and translation. 0 means identical shape, 1 means unrelated
+func ProcrustesDistance(a openpose.Human, b openpose.Human, imgW float64, imgH float64) (float64, bool) {
+	pa, pb, weights, ok := common(a, b, imgW, imgH)
+	if !ok {
+		return 0, false
+	}
+	// treat points as complex numbers, the optimal similarity transform is z = sum(a*conj(b))/sum(|b|^2)
+	var re, im, nb, na float64
+	for idx, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		p, q := pa.Points[idx], pb.Points[idx]
+		re += w * (p.X*q.X + p.Y*q.Y)
+		im += w * (p.Y*q.X - p.X*q.Y)
+		nb += w * q.Dot(q)
+		na += w * p.Dot(p)
+	}
+	if na <= 1e-15 || nb <= 1e-15 {
+		return 0, false
+	}
+	// residual = |a|^2 - |sum(a*conj(b))|^2/|b|^2
+	residual := math.Max(0, na-(re*re+im*im)/nb)
+	return math.Sqrt(residual / na), true
+}
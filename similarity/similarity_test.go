@@ -0,0 +1,92 @@
+package similarity
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/internal/posetest"
+)
+
+var testPose = map[openpose.CocoPart]openpose.Point{
+	openpose.CocoPartNose:      openpose.Pt(0.50, 0.10),
+	openpose.CocoPartNeck:      openpose.Pt(0.50, 0.20),
+	openpose.CocoPartRShoulder: openpose.Pt(0.42, 0.20),
+	openpose.CocoPartRElbow:    openpose.Pt(0.40, 0.32),
+	openpose.CocoPartRWrist:    openpose.Pt(0.39, 0.42),
+	openpose.CocoPartLShoulder: openpose.Pt(0.58, 0.20),
+	openpose.CocoPartLElbow:    openpose.Pt(0.60, 0.32),
+	openpose.CocoPartLWrist:    openpose.Pt(0.64, 0.40),
+	openpose.CocoPartRHip:      openpose.Pt(0.45, 0.45),
+	openpose.CocoPartRKnee:     openpose.Pt(0.45, 0.65),
+	openpose.CocoPartRAnkle:    openpose.Pt(0.44, 0.85),
+	openpose.CocoPartLHip:      openpose.Pt(0.55, 0.45),
+	openpose.CocoPartLKnee:     openpose.Pt(0.56, 0.65),
+	openpose.CocoPartLAnkle:    openpose.Pt(0.57, 0.85),
+}
+
+func newHuman(transform func(openpose.Point) openpose.Point, skip ...openpose.CocoPart) openpose.Human {
+	points := make(map[openpose.CocoPart]openpose.Point, len(testPose))
+	for part, point := range testPose {
+		points[part] = transform(point)
+	}
+	for _, part := range skip {
+		delete(points, part)
+	}
+	// test pose points are normalized
+	return posetest.Human(points, 1, 1)
+}
+
+func identity(p openpose.Point) openpose.Point {
+	return p
+}
+
+func TestOKS_IdenticalPoseIsOne(t *testing.T) {
+	h := newHuman(identity)
+	assert.InDelta(t, 1, OKS(h, h, 640, 480, 0), 1e-9)
+}
+
+func TestOKS_MissingDetectedPartLowersScore(t *testing.T) {
+	gt := newHuman(identity)
+	dt := newHuman(identity, openpose.CocoPartRWrist)
+	oks := OKS(gt, dt, 640, 480, 0)
+	assert.InDelta(t, 13.0/14.0, oks, 1e-9)
+}
+
+func TestWeightedCosine_InvariantToTranslationAndScale(t *testing.T) {
+	a := newHuman(identity)
+	b := newHuman(func(p openpose.Point) openpose.Point {
+		return openpose.Pt(p.X*0.5+0.2, p.Y*0.5+0.1)
+	}, openpose.CocoPartLWrist)
+	cos, ok := WeightedCosine(a, b, 640, 480)
+	assert.True(t, ok)
+	assert.InDelta(t, 1, cos, 1e-9)
+	dist, ok := NormalizedDistance(a, b, 640, 480)
+	assert.True(t, ok)
+	assert.InDelta(t, 0, dist, 1e-9)
+}
+
+func TestProcrustesDistance_InvariantToRotation(t *testing.T) {
+	a := newHuman(identity)
+	theta := math.Pi / 6
+	b := newHuman(func(p openpose.Point) openpose.Point {
+		return openpose.Pt(
+			p.X*math.Cos(theta)-p.Y*math.Sin(theta),
+			p.X*math.Sin(theta)+p.Y*math.Cos(theta),
+		)
+	})
+	dist, ok := ProcrustesDistance(a, b, 100, 100)
+	assert.True(t, ok)
+	assert.InDelta(t, 0, dist, 1e-6)
+	cos, _ := WeightedCosine(a, b, 100, 100)
+	assert.Less(t, cos, 0.99)
+}
+
+func TestProcrustesDistance_RequiresCommonParts(t *testing.T) {
+	a := openpose.NewHuman()
+	a.Parts[openpose.CocoPartNose] = openpose.NewBodyPart(openpose.CocoPartNose, openpose.Pt(0.5, 0.5), 0.9)
+	_, ok := ProcrustesDistance(*a, newHuman(identity), 100, 100)
+	assert.False(t, ok)
+}
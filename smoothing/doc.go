@@ -0,0 +1,2 @@
+// Package smoothing temporal keypoint smoothing filters
+package smoothing
@@ -0,0 +1,177 @@
+package smoothing
+
+import (
+	"math"
+	"time"
+
+	"github.com/bububa/openpose"
+)
+
+// Filter represents a temporal point filter
+type Filter interface {
+	// Filter returns filtered point of measurement p at t
+	Filter(p openpose.Point, t time.Time) openpose.Point
+	// Reset drops filter state
+	Reset()
+}
+
+// OneEuroConfig represents One Euro filter params, see https://gery.casiez.net/1euro/
+type OneEuroConfig struct {
+	// MinCutoff min cutoff frequency in Hz, lower value removes more jitter at low speed
+	MinCutoff float64
+	// Beta speed coefficient, higher value reduces lag at high speed
+	Beta float64
+	// DCutoff cutoff frequency of the derivative in Hz
+	DCutoff float64
+}
+
+// DefaultOneEuroConfig returns default One Euro filter params for pixel coordinates
+func DefaultOneEuroConfig() OneEuroConfig {
+	return OneEuroConfig{
+		MinCutoff: 1,
+		Beta:      0.05,
+		DCutoff:   1,
+	}
+}
+
+// OneEuro represents One Euro filter
+type OneEuro struct {
+	cfg  OneEuroConfig
+	x    openpose.Point
+	dx   openpose.Point
+	last time.Time
+	init bool
+}
+
+// NewOneEuro returns a new One Euro filter
+func NewOneEuro(cfg OneEuroConfig) *OneEuro {
+	return &OneEuro{cfg: cfg}
+}
+
+// Filter implements Filter interface
+func (f *OneEuro) Filter(p openpose.Point, t time.Time) openpose.Point {
+	if !f.init {
+		f.x = p
+		f.dx = openpose.ZP
+		f.last = t
+		f.init = true
+		return p
+	}
+	dt := t.Sub(f.last).Seconds()
+	if dt <= 0 {
+		return f.x
+	}
+	f.last = t
+	dx := p.Sub(f.x).Mul(1 / dt)
+	f.dx = lowPass(f.dx, dx, smoothingFactor(dt, f.cfg.DCutoff))
+	cutoff := f.cfg.MinCutoff + f.cfg.Beta*f.dx.Norm()
+	f.x = lowPass(f.x, p, smoothingFactor(dt, cutoff))
+	return f.x
+}
+
+// Reset implements Filter interface
+func (f *OneEuro) Reset() {
+	f.init = false
+}
+
+func smoothingFactor(dt float64, cutoff float64) float64 {
+	r := 2 * math.Pi * cutoff * dt
+	return r / (r + 1)
+}
+
+func lowPass(prev openpose.Point, value openpose.Point, alpha float64) openpose.Point {
+	return value.Mul(alpha).Add(prev.Mul(1 - alpha))
+}
+
+// KalmanConfig represents constant velocity Kalman filter params
+type KalmanConfig struct {
+	// ProcessNoise acceleration noise spectral density in pixels^2/s^3
+	ProcessNoise float64
+	// MeasurementNoise measurement noise variance in pixels^2
+	MeasurementNoise float64
+}
+
+// DefaultKalmanConfig returns default Kalman filter params for pixel coordinates
+func DefaultKalmanConfig() KalmanConfig {
+	return KalmanConfig{
+		ProcessNoise:     500,
+		MeasurementNoise: 16,
+	}
+}
+
+// kalmanAxis represents constant velocity Kalman state of an axis
+type kalmanAxis struct {
+	x float64
+	v float64
+	// covariance [[p00, p01], [p01, p11]]
+	p00 float64
+	p01 float64
+	p11 float64
+}
+
+func (k *kalmanAxis) reset(x float64, r float64) {
+	k.x = x
+	k.v = 0
+	k.p00 = r
+	k.p01 = 0
+	// velocity is unknown
+	k.p11 = r * 100
+}
+
+func (k *kalmanAxis) update(z float64, dt float64, q float64, r float64) float64 {
+	// predict, F = [[1, dt], [0, 1]], Q of white noise acceleration
+	k.x += k.v * dt
+	dt2 := dt * dt
+	p00 := k.p00 + 2*dt*k.p01 + dt2*k.p11 + q*dt2*dt/3
+	p01 := k.p01 + dt*k.p11 + q*dt2/2
+	p11 := k.p11 + q*dt
+	// update, H = [1, 0]
+	s := p00 + r
+	k0, k1 := p00/s, p01/s
+	y := z - k.x
+	k.x += k0 * y
+	k.v += k1 * y
+	k.p00 = (1 - k0) * p00
+	k.p01 = (1 - k0) * p01
+	k.p11 = p11 - k1*p01
+	return k.x
+}
+
+// Kalman represents constant velocity Kalman filter of a point
+type Kalman struct {
+	cfg  KalmanConfig
+	x    kalmanAxis
+	y    kalmanAxis
+	last time.Time
+	init bool
+}
+
+// NewKalman returns a new constant velocity Kalman filter
+func NewKalman(cfg KalmanConfig) *Kalman {
+	return &Kalman{cfg: cfg}
+}
+
+// Filter implements Filter interface
+func (f *Kalman) Filter(p openpose.Point, t time.Time) openpose.Point {
+	if !f.init {
+		f.x.reset(p.X, f.cfg.MeasurementNoise)
+		f.y.reset(p.Y, f.cfg.MeasurementNoise)
+		f.last = t
+		f.init = true
+		return p
+	}
+	dt := t.Sub(f.last).Seconds()
+	if dt <= 0 {
+		return openpose.Pt(f.x.x, f.y.x)
+	}
+	f.last = t
+	return openpose.Pt(
+		f.x.update(p.X, dt, f.cfg.ProcessNoise, f.cfg.MeasurementNoise),
+		f.y.update(p.Y, dt, f.cfg.ProcessNoise, f.cfg.MeasurementNoise),
+	)
+}
+
+// Reset implements Filter interface
+func (f *Kalman) Reset() {
+	f.init = false
+}
@@ -0,0 +1,82 @@
+package smoothing
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bububa/openpose"
+)
+
+// partFilter represents filter state of a body part
+type partFilter struct {
+	filter Filter
+	seen   time.Time
+}
+
+// Smoother smooths body part points per track and per part
+type Smoother struct {
+	newFilter func() Filter
+	maxGap    time.Duration
+	filters   map[int]map[openpose.CocoPart]*partFilter
+	mutex     sync.Mutex
+}
+
+// NewSmoother returns a new Smoother, newFilter creates a filter for each track part.
+// A part missing longer than maxGap restarts its filter when it reappears
+func NewSmoother(newFilter func() Filter, maxGap time.Duration) *Smoother {
+	return &Smoother{
+		newFilter: newFilter,
+		maxGap:    maxGap,
+		filters:   make(map[int]map[openpose.CocoPart]*partFilter),
+	}
+}
+
+// NewOneEuroSmoother returns a new Smoother of One Euro filters
+func NewOneEuroSmoother(cfg OneEuroConfig, maxGap time.Duration) *Smoother {
+	return NewSmoother(func() Filter { return NewOneEuro(cfg) }, maxGap)
+}
+
+// NewKalmanSmoother returns a new Smoother of Kalman filters
+func NewKalmanSmoother(cfg KalmanConfig, maxGap time.Duration) *Smoother {
+	return NewSmoother(func() Filter { return NewKalman(cfg) }, maxGap)
+}
+
+// Smooth returns human of track with smoothed points in pixel space compared to img size (w, h) at t.
+// Missing parts stay missing
+func (s *Smoother) Smooth(trackID int, h openpose.Human, imgW float64, imgH float64, t time.Time) openpose.Human {
+	if imgW <= 0 || imgH <= 0 {
+		return h
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	filters, found := s.filters[trackID]
+	if !found {
+		filters = make(map[openpose.CocoPart]*partFilter, openpose.TotalBodyParts)
+		s.filters[trackID] = filters
+	}
+	ret := openpose.Human{
+		Parts: make(map[openpose.CocoPart]openpose.BodyPart, len(h.Parts)),
+		Score: h.Score,
+	}
+	for part, bodyPart := range h.Parts {
+		pf, found := filters[part]
+		if !found {
+			pf = &partFilter{filter: s.newFilter()}
+			filters[part] = pf
+		} else if t.Sub(pf.seen) > s.maxGap {
+			pf.filter.Reset()
+		}
+		pf.seen = t
+		point := pf.filter.Filter(bodyPart.Point.Scale(imgW, imgH), t)
+		bodyPart.Point = openpose.Pt(point.X/imgW, point.Y/imgH)
+		ret.Parts[part] = bodyPart
+	}
+	return ret
+}
+
+// Forget drops filters of track
+func (s *Smoother) Forget(trackID int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.filters, trackID)
+}
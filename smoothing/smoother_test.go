@@ -0,0 +1,73 @@
+package smoothing
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bububa/openpose"
+)
+
+func jitter(rnd *rand.Rand, p openpose.Point, amount float64) openpose.Point {
+	return openpose.Pt(p.X+(rnd.Float64()-0.5)*amount, p.Y+(rnd.Float64()-0.5)*amount)
+}
+
+func TestFilters_ReduceJitterOfStillPoint(t *testing.T) {
+	filters := map[string]Filter{
+		"oneeuro": NewOneEuro(DefaultOneEuroConfig()),
+		"kalman":  NewKalman(DefaultKalmanConfig()),
+	}
+	for name, f := range filters {
+		rnd := rand.New(rand.NewSource(1))
+		still := openpose.Pt(320, 240)
+		start := time.Now()
+		var rawErr, filteredErr float64
+		for frame := 0; frame < 100; frame++ {
+			measured := jitter(rnd, still, 8)
+			filtered := f.Filter(measured, start.Add(time.Duration(frame)*33*time.Millisecond))
+			if frame < 10 {
+				continue
+			}
+			rawErr += measured.Distance(still)
+			filteredErr += filtered.Distance(still)
+		}
+		assert.Less(t, filteredErr, rawErr*0.75, name)
+	}
+}
+
+func TestFilters_FollowMovingPoint(t *testing.T) {
+	filters := map[string]Filter{
+		"oneeuro": NewOneEuro(DefaultOneEuroConfig()),
+		"kalman":  NewKalman(DefaultKalmanConfig()),
+	}
+	for name, f := range filters {
+		start := time.Now()
+		var last openpose.Point
+		for frame := 0; frame < 60; frame++ {
+			p := openpose.Pt(100+float64(frame)*10, 200)
+			last = f.Filter(p, start.Add(time.Duration(frame)*33*time.Millisecond))
+		}
+		assert.InDelta(t, 690, last.X, 15, name)
+	}
+}
+
+func TestSmoother_RestartsAfterGap(t *testing.T) {
+	s := NewOneEuroSmoother(DefaultOneEuroConfig(), 200*time.Millisecond)
+	start := time.Now()
+	h := openpose.NewHuman()
+	h.Parts[openpose.CocoPartRWrist] = openpose.NewBodyPart(openpose.CocoPartRWrist, openpose.Pt(0.1, 0.1), 0.9)
+	for frame := 0; frame < 5; frame++ {
+		s.Smooth(1, *h, 640, 480, start.Add(time.Duration(frame)*33*time.Millisecond))
+	}
+	// the part is missing in the output when it's missing in the input
+	out := s.Smooth(1, openpose.Human{}, 640, 480, start.Add(200*time.Millisecond))
+	assert.False(t, out.HasPart(openpose.CocoPartRWrist))
+	// reappears far away after the gap, the filter restarts at the new position
+	h.Parts[openpose.CocoPartRWrist] = openpose.NewBodyPart(openpose.CocoPartRWrist, openpose.Pt(0.9, 0.9), 0.9)
+	out = s.Smooth(1, *h, 640, 480, start.Add(time.Second))
+	p := out.Parts[openpose.CocoPartRWrist].Point
+	assert.True(t, math.Abs(p.X-0.9) < 1e-9 && math.Abs(p.Y-0.9) < 1e-9)
+}
@@ -0,0 +1,206 @@
+package swap
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/bububa/openpose"
+)
+
+// Config represents swap corrector config
+type Config struct {
+	// MaxGap max duration to the previous frame of a track for temporal checks
+	MaxGap time.Duration
+	// MaxCostRatio max ratio of swapped to kept displacement to swap a pair
+	MaxCostRatio float64
+	// MinDisplacement min displacement saved by swapping a pair, in torso lengths
+	MinDisplacement float64
+}
+
+// DefaultConfig returns default swap corrector config
+func DefaultConfig() Config {
+	return Config{
+		MaxGap:          500 * time.Millisecond,
+		MaxCostRatio:    0.5,
+		MinDisplacement: 0.15,
+	}
+}
+
+// history represents the last corrected frame of a track
+type history struct {
+	points map[openpose.CocoPart]openpose.Point
+	// orientation sign of right to left shoulder or hip x, positive when facing the camera
+	orientation float64
+	scale       float64
+	time        time.Time
+}
+
+// Corrector corrects left/right swaps of tracked humans frame by frame
+type Corrector struct {
+	cfg     Config
+	history map[int]*history
+	mutex   sync.Mutex
+}
+
+// NewCorrector returns a new Corrector
+func NewCorrector(cfg Config) *Corrector {
+	return &Corrector{
+		cfg:     cfg,
+		history: make(map[int]*history),
+	}
+}
+
+// Correct returns human of track at t with swapped left/right parts corrected, and the right parts of swapped pairs.
+// Points are compared in pixel space compared to img size (w, h)
+func (c *Corrector) Correct(trackID int, h openpose.Human, imgW float64, imgH float64, t time.Time) (openpose.Human, []openpose.CocoPart) {
+	if imgW <= 0 || imgH <= 0 {
+		return h, nil
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	ret := openpose.Human{
+		Parts: make(map[openpose.CocoPart]openpose.BodyPart, len(h.Parts)),
+		Score: h.Score,
+	}
+	for part, bodyPart := range h.Parts {
+		ret.Parts[part] = bodyPart
+	}
+	var swapped []openpose.CocoPart
+	prev, found := c.history[trackID]
+	if found && t.Sub(prev.time) <= c.cfg.MaxGap {
+		swapped = c.correct(&ret, prev, imgW, imgH)
+	}
+	c.history[trackID] = newHistory(ret, imgW, imgH, t, prev)
+	return ret, swapped
+}
+
+// Forget drops history of track
+func (c *Corrector) Forget(trackID int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.history, trackID)
+}
+
+// correct swaps pairs of h which are closer to the opposite parts of prev, and torso pairs disagreeing with prev orientation
+func (c *Corrector) correct(h *openpose.Human, prev *history, imgW float64, imgH float64) []openpose.CocoPart {
+	var swapped []openpose.CocoPart
+	for _, pair := range openpose.CocoPartPairsLR {
+		if c.temporalSwap(*h, prev, pair, imgW, imgH) {
+			swapPair(h, pair)
+			swapped = append(swapped, pair[0])
+		}
+	}
+	// shoulders and hips of a torso face the same way, the pair flipped since the previous frame is swapped
+	if prev.orientation == 0 {
+		return swapped
+	}
+	// pairs seen from the side overlap and their order is noise
+	minWidth := c.cfg.MinDisplacement * prev.scale
+	shoulders, foundShoulders := orientation(*h, openpose.CocoPartRShoulder, minWidth, imgW, imgH)
+	hips, foundHips := orientation(*h, openpose.CocoPartRHip, minWidth, imgW, imgH)
+	if !foundShoulders || !foundHips || shoulders == hips {
+		return swapped
+	}
+	flipped := openpose.CocoPartRShoulder
+	if hips != prev.orientation {
+		flipped = openpose.CocoPartRHip
+	}
+	if !contains(swapped, flipped) {
+		swapPair(h, [2]openpose.CocoPart{flipped, flipped.Mirror()})
+		swapped = append(swapped, flipped)
+	}
+	return swapped
+}
+
+// temporalSwap returns the pair of h moved less if swapped compared to prev
+func (c *Corrector) temporalSwap(h openpose.Human, prev *history, pair [2]openpose.CocoPart, imgW float64, imgH float64) bool {
+	prevR, foundPrevR := prev.points[pair[0]]
+	prevL, foundPrevL := prev.points[pair[1]]
+	if !foundPrevR || !foundPrevL {
+		return false
+	}
+	r, foundR := h.PixelCenter([]openpose.CocoPart{pair[0]}, imgW, imgH)
+	l, foundL := h.PixelCenter([]openpose.CocoPart{pair[1]}, imgW, imgH)
+	var keep, swap float64
+	switch {
+	case foundR && foundL:
+		keep = r.Distance(prevR) + l.Distance(prevL)
+		swap = r.Distance(prevL) + l.Distance(prevR)
+	case foundR:
+		keep, swap = r.Distance(prevR), r.Distance(prevL)
+	case foundL:
+		keep, swap = l.Distance(prevL), l.Distance(prevR)
+	default:
+		return false
+	}
+	return swap < keep*c.cfg.MaxCostRatio && keep-swap > c.cfg.MinDisplacement*prev.scale
+}
+
+// newHistory returns history of corrected human, orientation and scale are kept from prev if not computable
+func newHistory(h openpose.Human, imgW float64, imgH float64, t time.Time, prev *history) *history {
+	ret := &history{
+		points: make(map[openpose.CocoPart]openpose.Point, len(h.Parts)),
+		time:   t,
+		scale:  math.Hypot(imgW, imgH) * 0.1,
+	}
+	for part := range h.Parts {
+		if point, found := h.PixelCenter([]openpose.CocoPart{part}, imgW, imgH); found {
+			ret.points[part] = point
+		}
+	}
+	if torso, found := h.TorsoLength(imgW, imgH); found && torso > 0 {
+		ret.scale = torso
+	} else if prev != nil {
+		ret.scale = prev.scale
+	}
+	shoulders, foundShoulders := orientation(h, openpose.CocoPartRShoulder, 0, imgW, imgH)
+	hips, foundHips := orientation(h, openpose.CocoPartRHip, 0, imgW, imgH)
+	switch {
+	case foundShoulders && (!foundHips || shoulders == hips):
+		ret.orientation = shoulders
+	case foundHips && !foundShoulders:
+		ret.orientation = hips
+	case prev != nil:
+		ret.orientation = prev.orientation
+	}
+	return ret
+}
+
+// orientation returns sign of x from right part to its mirror, false if they are closer than minWidth in x
+func orientation(h openpose.Human, right openpose.CocoPart, minWidth float64, imgW float64, imgH float64) (float64, bool) {
+	r, foundR := h.PixelCenter([]openpose.CocoPart{right}, imgW, imgH)
+	l, foundL := h.PixelCenter([]openpose.CocoPart{right.Mirror()}, imgW, imgH)
+	if !foundR || !foundL || math.Abs(l.X-r.X) <= minWidth {
+		return 0, false
+	}
+	if l.X > r.X {
+		return 1, true
+	}
+	return -1, true
+}
+
+// swapPair swaps points and scores of a right and left part pair
+func swapPair(h *openpose.Human, pair [2]openpose.CocoPart) {
+	r, foundR := h.Parts[pair[0]]
+	l, foundL := h.Parts[pair[1]]
+	delete(h.Parts, pair[0])
+	delete(h.Parts, pair[1])
+	if foundR {
+		r.Part = pair[1]
+		h.Parts[pair[1]] = r
+	}
+	if foundL {
+		l.Part = pair[0]
+		h.Parts[pair[0]] = l
+	}
+}
+
+func contains(parts []openpose.CocoPart, part openpose.CocoPart) bool {
+	for _, p := range parts {
+		if p == part {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package swap
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/internal/posetest"
+)
+
+// newHuman returns a human facing the camera at pixel x in a 640x480 image, with body width scaled by facing in [-1, 1]
+func newHuman(x float64, facing float64) openpose.Human {
+	points := map[openpose.CocoPart]openpose.Point{
+		openpose.CocoPartNeck:      openpose.Pt(0, 80),
+		openpose.CocoPartRShoulder: openpose.Pt(-40, 80),
+		openpose.CocoPartLShoulder: openpose.Pt(40, 80),
+		openpose.CocoPartRElbow:    openpose.Pt(-50, 140),
+		openpose.CocoPartLElbow:    openpose.Pt(50, 140),
+		openpose.CocoPartRWrist:    openpose.Pt(-55, 200),
+		openpose.CocoPartLWrist:    openpose.Pt(55, 200),
+		openpose.CocoPartRHip:      openpose.Pt(-25, 200),
+		openpose.CocoPartLHip:      openpose.Pt(25, 200),
+		openpose.CocoPartRKnee:     openpose.Pt(-25, 300),
+		openpose.CocoPartLKnee:     openpose.Pt(25, 300),
+		openpose.CocoPartRAnkle:    openpose.Pt(-25, 400),
+		openpose.CocoPartLAnkle:    openpose.Pt(25, 400),
+	}
+	for part, point := range points {
+		points[part] = openpose.Pt(x+point.X*facing, point.Y)
+	}
+	return posetest.Human(points, 640, 480)
+}
+
+func TestCorrector_TemporalSwap(t *testing.T) {
+	c := NewCorrector(DefaultConfig())
+	start := time.Now()
+	for frame := 0; frame < 10; frame++ {
+		h := newHuman(300+float64(frame)*2, 1)
+		expected := h.Parts[openpose.CocoPartRWrist].Point
+		if frame >= 5 && frame%2 == 1 {
+			// labels flip back and forth
+			h.Parts[openpose.CocoPartRWrist], h.Parts[openpose.CocoPartLWrist] = h.Parts[openpose.CocoPartLWrist], h.Parts[openpose.CocoPartRWrist]
+		}
+		out, swapped := c.Correct(1, h, 640, 480, start.Add(time.Duration(frame)*33*time.Millisecond))
+		assert.Equal(t, expected, out.Parts[openpose.CocoPartRWrist].Point, "frame %d", frame)
+		assert.Equal(t, openpose.CocoPartRWrist, out.Parts[openpose.CocoPartRWrist].Part)
+		if frame >= 5 && frame%2 == 1 {
+			assert.Equal(t, []openpose.CocoPart{openpose.CocoPartRWrist}, swapped)
+		} else {
+			assert.Empty(t, swapped)
+		}
+	}
+}
+
+func TestCorrector_TurningAround(t *testing.T) {
+	c := NewCorrector(DefaultConfig())
+	start := time.Now()
+	for frame := 0; frame <= 60; frame++ {
+		facing := math.Cos(math.Pi * float64(frame) / 60)
+		_, swapped := c.Correct(1, newHuman(320, facing), 640, 480, start.Add(time.Duration(frame)*33*time.Millisecond))
+		assert.Empty(t, swapped, "frame %d", frame)
+	}
+}
@@ -0,0 +1,2 @@
+// Package swap detects and corrects left/right swapped body parts of tracked humans
+package swap
@@ -0,0 +1,209 @@
+package openpose
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+// matStride pixels of model input per heatmap and PAF cell
+const matStride = 8
+
+// TileConfig represents tiled estimation params of high resolution images
+type TileConfig struct {
+	// Size tile size in pixels fed to the model, rounded up to multiple of 8
+	Size ModelSize
+	// Overlap overlap of neighbour tiles in pixels, rounded up to multiple of 8.
+	// It should cover a limb of the smallest person so parts crossing tile borders are seen whole in a tile
+	Overlap int
+	// Scale image scale before tiling, 1 keeps native resolution
+	Scale float64
+}
+
+// DefaultTileConfig returns default tiled estimation params
+func DefaultTileConfig() TileConfig {
+	return TileConfig{
+		Size:    ModelSizeBetter,
+		Overlap: 96,
+		Scale:   1,
+	}
+}
+
+// tile represents a tile of the padded image
+type tile struct {
+	rect image.Rectangle
+	// blend overlap in mat cells at left, top, right and bottom, 0 at the image border
+	blend [4]int
+}
+
+// EstimateTiled returns estimated Humans in a high resolution image by tiles. Heatmaps and PAFs of overlapping tiles
+// are stitched with linear blending in the overlap, then humans are assembled once, so people crossing tile borders
+// are not split
+func (t *PoseEstimator) EstimateTiled(img image.Image, cfg TileConfig) ([]Human, error) {
+	if err := t.LoadModel(); err != nil {
+		return nil, err
+	}
+	tileW, tileH := roundUp(cfg.Size[0], matStride), roundUp(cfg.Size[1], matStride)
+	overlap := roundUp(cfg.Overlap, matStride)
+	if tileW <= 0 || tileH <= 0 {
+		return nil, errors.New("invalid tile size")
+	}
+	if overlap < 0 || overlap >= tileW || overlap >= tileH {
+		return nil, fmt.Errorf("overlap %d should be less than tile size %dx%d", overlap, tileW, tileH)
+	}
+	bounds := img.Bounds()
+	if cfg.Scale > 0 && cfg.Scale != 1 {
+		img = imaging.Resize(img, int(math.Round(float64(bounds.Dx())*cfg.Scale)), 0, imaging.Linear)
+		bounds = img.Bounds()
+	}
+	imgW, imgH := bounds.Dx(), bounds.Dy()
+	if imgW <= 0 || imgH <= 0 {
+		return nil, errors.New("empty image")
+	}
+	// pad to whole mat cells and at least a tile
+	padW, padH := roundUp(imgW, matStride), roundUp(imgH, matStride)
+	if padW < tileW {
+		padW = tileW
+	}
+	if padH < tileH {
+		padH = tileH
+	}
+	padded := image.NewRGBA(image.Rect(0, 0, padW, padH))
+	draw.Draw(padded, image.Rect(0, 0, imgW, imgH), img, bounds.Min, draw.Src)
+	rows, cols := padH/matStride, padW/matStride
+	var (
+		pafMat  [][][]float32
+		heatMat [][][]float32
+		weights = newMat(1, rows, cols)[0]
+	)
+	for _, tl := range tiles(padW, padH, tileW, tileH, overlap) {
+		var in image.Image = imaging.Crop(padded, tl.rect)
+		if t.sharpenSigma > 1e-15 {
+			in = imaging.Sharpen(in, t.sharpenSigma)
+		}
+		paf, heat, err := t.getMats(in)
+		if err != nil {
+			return nil, err
+		}
+		tileRows, tileCols := tl.rect.Dy()/matStride, tl.rect.Dx()/matStride
+		if len(heat) == 0 || len(heat[0]) != tileRows || len(heat[0][0]) != tileCols || len(paf) == 0 || len(paf[0]) != tileRows || len(paf[0][0]) != tileCols {
+			return nil, errors.New("unexpected model output size")
+		}
+		if heatMat == nil {
+			heatMat, pafMat = newMat(len(heat), rows, cols), newMat(len(paf), rows, cols)
+		}
+		offsetY, offsetX := tl.rect.Min.Y/matStride, tl.rect.Min.X/matStride
+		for y := 0; y < tileRows; y++ {
+			wy := blendWeight(y, tileRows, tl.blend[1], tl.blend[3])
+			for x := 0; x < tileCols; x++ {
+				w := wy * blendWeight(x, tileCols, tl.blend[0], tl.blend[2])
+				gy, gx := offsetY+y, offsetX+x
+				weights[gy][gx] += w
+				for c := range heat {
+					heatMat[c][gy][gx] += w * heat[c][y][x]
+				}
+				for c := range paf {
+					pafMat[c][gy][gx] += w * paf[c][y][x]
+				}
+			}
+		}
+	}
+	for y, row := range weights {
+		for x, w := range row {
+			if w <= 0 {
+				continue
+			}
+			for c := range heatMat {
+				heatMat[c][y][x] /= w
+			}
+			for c := range pafMat {
+				pafMat[c][y][x] /= w
+			}
+		}
+	}
+	normPadding := Size{
+		W: float64(imgW) / float64(padW),
+		H: float64(imgH) / float64(padH),
+	}
+	return t.assemblePose(pafMat, heatMat, normPadding)
+}
+
+// tiles returns tiles of tile size (w, h) covering image size (imgW, imgH), neighbours overlap by overlap pixels
+func tiles(imgW int, imgH int, w int, h int, overlap int) []tile {
+	xs, ys := tileOrigins(imgW, w, overlap), tileOrigins(imgH, h, overlap)
+	ret := make([]tile, 0, len(xs)*len(ys))
+	for j, y := range ys {
+		for i, x := range xs {
+			tl := tile{rect: image.Rect(x, y, x+w, y+h)}
+			// blend over the actual overlap with the neighbour, the last tile may overlap more
+			if i > 0 {
+				tl.blend[0] = (xs[i-1] + w - x) / matStride
+			}
+			if j > 0 {
+				tl.blend[1] = (ys[j-1] + h - y) / matStride
+			}
+			if i < len(xs)-1 {
+				tl.blend[2] = (x + w - xs[i+1]) / matStride
+			}
+			if j < len(ys)-1 {
+				tl.blend[3] = (y + h - ys[j+1]) / matStride
+			}
+			ret = append(ret, tl)
+		}
+	}
+	return ret
+}
+
+// tileOrigins returns tile origins along an axis of length covered by tiles of size, the last tile is aligned to the end.
+// The last step is stretched instead of adding a near duplicate tile if the overlap keeps at least half of overlap
+func tileOrigins(length int, size int, overlap int) []int {
+	if length <= size {
+		return []int{0}
+	}
+	step := size - overlap
+	var ret []int
+	for origin := 0; origin+size < length; origin += step {
+		ret = append(ret, origin)
+	}
+	last := len(ret) - 1
+	if slack := overlap / 2 / matStride * matStride; last > 0 && length-size-ret[last] <= slack {
+		ret[last] = length - size
+		return ret
+	}
+	return append(ret, length-size)
+}
+
+// blendWeight returns weight of cell idx of n cells, ramping up linearly over head cells at start and tail cells at end
+func blendWeight(idx int, n int, head int, tail int) float32 {
+	w := float32(1)
+	if head > 0 && idx < head {
+		w = float32(idx+1) / float32(head+1)
+	}
+	if tail > 0 && n-1-idx < tail {
+		if tw := float32(n-idx) / float32(tail+1); tw < w {
+			w = tw
+		}
+	}
+	return w
+}
+
+// newMat returns zero mat of (channels, rows, cols)
+func newMat(channels int, rows int, cols int) [][][]float32 {
+	ret := make([][][]float32, channels)
+	for c := range ret {
+		ret[c] = make([][]float32, rows)
+		for y := range ret[c] {
+			ret[c][y] = make([]float32, cols)
+		}
+	}
+	return ret
+}
+
+// roundUp returns v rounded up to multiple of m
+func roundUp(v int, m int) int {
+	return (v + m - 1) / m * m
+}
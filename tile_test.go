@@ -0,0 +1,78 @@
+package openpose
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTileOrigins(t *testing.T) {
+	tests := []struct {
+		length  int
+		size    int
+		overlap int
+		want    []int
+	}{
+		{96, 104, 40, []int{0}},
+		{104, 104, 40, []int{0}},
+		{168, 104, 40, []int{0, 64}},
+		// the last tile would only be one stride past the previous one
+		{176, 104, 40, []int{0, 72}},
+		{200, 104, 40, []int{0, 64, 96}},
+		{400, 104, 0, []int{0, 104, 208, 296}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tileOrigins(tt.length, tt.size, tt.overlap), "length %d", tt.length)
+	}
+}
+
+func TestTiles(t *testing.T) {
+	tests := []struct {
+		imgW, imgH int
+		w, h       int
+		overlap    int
+	}{
+		{432, 368, 104, 96, 40},
+		{176, 104, 104, 104, 40},
+		{640, 480, 432, 368, 96},
+		{400, 400, 104, 104, 0},
+	}
+	for _, tt := range tests {
+		rows, cols := tt.imgH/matStride, tt.imgW/matStride
+		weights := newMat(1, rows, cols)[0]
+		for _, tl := range tiles(tt.imgW, tt.imgH, tt.w, tt.h, tt.overlap) {
+			assert.Equal(t, image.Pt(tt.w, tt.h), tl.rect.Size())
+			assert.True(t, tl.rect.In(image.Rect(0, 0, tt.imgW, tt.imgH)), "%v", tl.rect)
+			assert.Zero(t, tl.rect.Min.X%matStride)
+			assert.Zero(t, tl.rect.Min.Y%matStride)
+			tileRows, tileCols := tt.h/matStride, tt.w/matStride
+			for y := 0; y < tileRows; y++ {
+				wy := blendWeight(y, tileRows, tl.blend[1], tl.blend[3])
+				for x := 0; x < tileCols; x++ {
+					w := wy * blendWeight(x, tileCols, tl.blend[0], tl.blend[2])
+					assert.Greater(t, w, float32(0))
+					weights[tl.rect.Min.Y/matStride+y][tl.rect.Min.X/matStride+x] += w
+				}
+			}
+		}
+		// every cell is covered and the blended weights sum to 1 across overlaps
+		for y, row := range weights {
+			for x, w := range row {
+				assert.InDelta(t, 1, w, 1e-5, "%dx%d cell (%d, %d)", tt.imgW, tt.imgH, x, y)
+			}
+		}
+	}
+}
+
+func TestTiles_SmallImage(t *testing.T) {
+	// images smaller than a tile get a single tile without blending, the image is padded to it
+	got := tiles(80, 64, 104, 96, 40)
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, image.Rect(0, 0, 104, 96), got[0].rect)
+		assert.Equal(t, [4]int{}, got[0].blend)
+	}
+	for idx := 0; idx < 13; idx++ {
+		assert.Equal(t, float32(1), blendWeight(idx, 13, 0, 0))
+	}
+}
@@ -0,0 +1,2 @@
+// Package tracker multi-person tracker assigning persistent ids across frames
+package tracker
@@ -0,0 +1,96 @@
+package tracker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bububa/openpose"
+)
+
+// State represents track state
+type State int
+
+const (
+	// Tentative track is not confirmed by enough hits yet
+	Tentative State = iota
+	// Confirmed track is confirmed
+	Confirmed
+	// Deleted track is deleted
+	Deleted
+)
+
+// Track represents a tracked human
+type Track struct {
+	// ID persistent track id
+	ID int
+	// Human last matched human
+	Human openpose.Human
+	// Box last matched full body box in pixels
+	Box openpose.Rectangle
+	// State track state
+	State State
+	// Hits total matched frames
+	Hits int
+	// Age frames since birth
+	Age int
+	// Misses consecutive frames without match
+	Misses int
+	// FirstSeen time of the first match
+	FirstSeen time.Time
+	// LastSeen time of the last match
+	LastSeen time.Time
+	// Velocity body center velocity in pixels per second
+	Velocity openpose.Point
+}
+
+// String returns Track string representation
+func (t Track) String() string {
+	return fmt.Sprintf("Track:%d hits:%d misses:%d", t.ID, t.Hits, t.Misses)
+}
+
+// Predict returns human and box moved by velocity to t
+func (t Track) Predict(ts time.Time, imgW float64, imgH float64) (openpose.Human, openpose.Rectangle) {
+	dt := ts.Sub(t.LastSeen).Seconds()
+	if dt <= 0 || (t.Velocity.X == 0 && t.Velocity.Y == 0) || imgW <= 0 || imgH <= 0 {
+		return t.Human, t.Box
+	}
+	shift := t.Velocity.Mul(dt)
+	h := openpose.Human{
+		Parts: make(map[openpose.CocoPart]openpose.BodyPart, len(t.Human.Parts)),
+		Score: t.Human.Score,
+	}
+	normShift := openpose.Pt(shift.X/imgW, shift.Y/imgH)
+	for part, bodyPart := range t.Human.Parts {
+		bodyPart.Point = bodyPart.Point.Add(normShift)
+		h.Parts[part] = bodyPart
+	}
+	box := t.Box
+	box.X += openpose.RoundInt(shift.X)
+	box.Y += openpose.RoundInt(shift.Y)
+	return h, box
+}
+
+// EventKind represents track event kind
+type EventKind int
+
+const (
+	// Birth track is confirmed
+	Birth EventKind = iota
+	// Death confirmed track is deleted
+	Death
+)
+
+// String returns EventKind name
+func (k EventKind) String() string {
+	if k == Birth {
+		return "birth"
+	}
+	return "death"
+}
+
+// Event represents track birth or death
+type Event struct {
+	Kind  EventKind
+	Track Track
+	Time  time.Time
+}
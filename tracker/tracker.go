@@ -0,0 +1,192 @@
+package tracker
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/similarity"
+)
+
+// Matching represents similarity used to match humans to tracks
+type Matching int
+
+const (
+	// MatchOKS matches by object keypoint similarity
+	MatchOKS Matching = iota
+	// MatchIoU matches by full body box intersection over union
+	MatchIoU
+)
+
+// Config represents tracker config
+type Config struct {
+	// Matching similarity used to match humans to tracks
+	Matching Matching
+	// MinSimilarity min similarity of a match
+	MinSimilarity float64
+	// MaxAge max consecutive frames a confirmed track could miss before deleted
+	MaxAge int
+	// MinHits hits required to confirm a track
+	MinHits int
+	// VelocitySmoothing weight of the latest velocity in exponential smoothing, 0 disables motion model
+	VelocitySmoothing float64
+}
+
+// DefaultConfig returns default tracker config
+func DefaultConfig() Config {
+	return Config{
+		Matching:          MatchOKS,
+		MinSimilarity:     0.3,
+		MaxAge:            30,
+		MinHits:           3,
+		VelocitySmoothing: 0.5,
+	}
+}
+
+// Tracker assigns persistent track ids to humans of successive frames
+type Tracker struct {
+	cfg    Config
+	tracks []*Track
+	nextID int
+	mutex  sync.Mutex
+}
+
+// New returns a new Tracker
+func New(cfg Config) *Tracker {
+	return &Tracker{
+		cfg:    cfg,
+		nextID: 1,
+	}
+}
+
+// Tracks returns all alive tracks including tentative and coasting ones
+func (t *Tracker) Tracks() []Track {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	ret := make([]Track, 0, len(t.tracks))
+	for _, track := range t.tracks {
+		ret = append(ret, *track)
+	}
+	return ret
+}
+
+// Reset drops all tracks
+func (t *Tracker) Reset() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.tracks = nil
+}
+
+type candidate struct {
+	track      int
+	human      int
+	similarity float64
+}
+
+// Update matches humans of a frame in pixel space compared to img size (w, h) at ts to tracks.
+// It returns confirmed tracks matched in this frame, and birth and death events
+func (t *Tracker) Update(humans []openpose.Human, imgW float64, imgH float64, ts time.Time) ([]Track, []Event) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	boxes := make([]openpose.Rectangle, len(humans))
+	for idx, h := range humans {
+		boxes[idx] = h.GetBodyBox(imgW, imgH, 0, 0)
+	}
+	// greedy matching by similarity, same as part connections
+	candidates := make([]candidate, 0, len(t.tracks)*len(humans))
+	for trackIdx, track := range t.tracks {
+		predicted, predictedBox := track.Predict(ts, imgW, imgH)
+		for humanIdx, h := range humans {
+			var score float64
+			if t.cfg.Matching == MatchIoU {
+				score = predictedBox.IoU(boxes[humanIdx])
+			} else {
+				score = similarity.OKS(predicted, h, imgW, imgH, float64(predictedBox.Area()))
+			}
+			if score < t.cfg.MinSimilarity {
+				continue
+			}
+			candidates = append(candidates, candidate{trackIdx, humanIdx, score})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].similarity > candidates[j].similarity })
+	var (
+		usedTracks = make(map[int]struct{}, len(t.tracks))
+		usedHumans = make(map[int]struct{}, len(humans))
+		events     []Event
+	)
+	for _, c := range candidates {
+		_, foundTrack := usedTracks[c.track]
+		_, foundHuman := usedHumans[c.human]
+		if foundTrack || foundHuman {
+			continue
+		}
+		usedTracks[c.track] = struct{}{}
+		usedHumans[c.human] = struct{}{}
+		track := t.tracks[c.track]
+		t.update(track, humans[c.human], boxes[c.human], ts)
+		if track.State == Tentative && track.Hits >= t.cfg.MinHits {
+			track.State = Confirmed
+			events = append(events, Event{Kind: Birth, Track: *track, Time: ts})
+		}
+	}
+	alive := make([]*Track, 0, len(t.tracks)+len(humans))
+	for idx, track := range t.tracks {
+		if _, found := usedTracks[idx]; !found {
+			track.Age++
+			track.Misses++
+			// tentative tracks are dropped once missed
+			if track.State == Tentative {
+				continue
+			}
+			if track.Misses > t.cfg.MaxAge {
+				track.State = Deleted
+				events = append(events, Event{Kind: Death, Track: *track, Time: ts})
+				continue
+			}
+		}
+		alive = append(alive, track)
+	}
+	for idx, h := range humans {
+		if _, found := usedHumans[idx]; found {
+			continue
+		}
+		track := &Track{
+			ID:        t.nextID,
+			Human:     h,
+			Box:       boxes[idx],
+			State:     Tentative,
+			Hits:      1,
+			FirstSeen: ts,
+			LastSeen:  ts,
+		}
+		t.nextID++
+		if track.Hits >= t.cfg.MinHits {
+			track.State = Confirmed
+			events = append(events, Event{Kind: Birth, Track: *track, Time: ts})
+		}
+		alive = append(alive, track)
+	}
+	t.tracks = alive
+	ret := make([]Track, 0, len(alive))
+	for _, track := range alive {
+		if track.State == Confirmed && track.Misses == 0 {
+			ret = append(ret, *track)
+		}
+	}
+	return ret, events
+}
+
+func (t *Tracker) update(track *Track, h openpose.Human, box openpose.Rectangle, ts time.Time) {
+	if dt := ts.Sub(track.LastSeen).Seconds(); dt > 0 && t.cfg.VelocitySmoothing > 0 {
+		velocity := box.Center().Sub(track.Box.Center()).Mul(1 / dt)
+		track.Velocity = velocity.Mul(t.cfg.VelocitySmoothing).Add(track.Velocity.Mul(1 - t.cfg.VelocitySmoothing))
+	}
+	track.Human = h
+	track.Box = box
+	track.Hits++
+	track.Age++
+	track.Misses = 0
+	track.LastSeen = ts
+}
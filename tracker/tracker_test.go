@@ -0,0 +1,101 @@
+package tracker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bububa/openpose"
+	"github.com/bububa/openpose/internal/posetest"
+)
+
+// newHuman returns a standing human at pixel x in a 640x480 image
+func newHuman(x float64) openpose.Human {
+	points := map[openpose.CocoPart]openpose.Point{
+		openpose.CocoPartNose:      openpose.Pt(0, 40),
+		openpose.CocoPartNeck:      openpose.Pt(0, 80),
+		openpose.CocoPartRShoulder: openpose.Pt(-30, 80),
+		openpose.CocoPartLShoulder: openpose.Pt(30, 80),
+		openpose.CocoPartRHip:      openpose.Pt(-20, 200),
+		openpose.CocoPartLHip:      openpose.Pt(20, 200),
+		openpose.CocoPartRKnee:     openpose.Pt(-20, 300),
+		openpose.CocoPartLKnee:     openpose.Pt(20, 300),
+		openpose.CocoPartRAnkle:    openpose.Pt(-20, 400),
+		openpose.CocoPartLAnkle:    openpose.Pt(20, 400),
+	}
+	for part, point := range points {
+		points[part] = openpose.Pt(x+point.X, point.Y)
+	}
+	h := posetest.Human(points, 640, 480)
+	h.Score = 0.9
+	return h
+}
+
+func ids(tracks []Track) map[int]float64 {
+	ret := make(map[int]float64, len(tracks))
+	for _, track := range tracks {
+		ret[track.ID] = track.Human.Parts[openpose.CocoPartNeck].Point.X * 640
+	}
+	return ret
+}
+
+func TestTracker_PersistentIDs(t *testing.T) {
+	for _, matching := range []Matching{MatchOKS, MatchIoU} {
+		cfg := DefaultConfig()
+		cfg.Matching = matching
+		tr := New(cfg)
+		start := time.Now()
+		var (
+			births int
+			last   map[int]float64
+		)
+		for frame := 0; frame < 10; frame++ {
+			ts := start.Add(time.Duration(frame) * 100 * time.Millisecond)
+			// two people walking towards each other, input order swapped every frame
+			a, b := newHuman(150+float64(frame)*8), newHuman(500-float64(frame)*8)
+			humans := []openpose.Human{a, b}
+			if frame%2 == 1 {
+				humans = []openpose.Human{b, a}
+			}
+			tracks, events := tr.Update(humans, 640, 480, ts)
+			for _, event := range events {
+				assert.Equal(t, Birth, event.Kind)
+				births++
+			}
+			if frame < cfg.MinHits-1 {
+				assert.Empty(t, tracks)
+				continue
+			}
+			current := ids(tracks)
+			assert.Len(t, current, 2)
+			if last != nil {
+				for id, x := range current {
+					assert.InDelta(t, last[id], x, 10, "track %d jumped", id)
+				}
+			}
+			last = current
+		}
+		assert.Equal(t, 2, births)
+	}
+}
+
+func TestTracker_DeathAfterMaxAge(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MaxAge = 2
+	tr := New(cfg)
+	start := time.Now()
+	for frame := 0; frame < cfg.MinHits; frame++ {
+		tr.Update([]openpose.Human{newHuman(300)}, 640, 480, start.Add(time.Duration(frame)*time.Second))
+	}
+	var deaths []Event
+	for frame := cfg.MinHits; frame < cfg.MinHits+4; frame++ {
+		_, events := tr.Update(nil, 640, 480, start.Add(time.Duration(frame)*time.Second))
+		deaths = append(deaths, events...)
+	}
+	if assert.Len(t, deaths, 1) {
+		assert.Equal(t, Death, deaths[0].Kind)
+		assert.Equal(t, 1, deaths[0].Track.ID)
+	}
+	assert.Empty(t, tr.Tracks())
+}
@@ -0,0 +1,279 @@
+package openpose
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+// Affine represents a 2D affine transform of pixel coordinates, [x', y'] = [[a, b, c], [d, e, f]] * [x, y, 1]
+type Affine [2][3]float64
+
+// IdentityAffine returns identity transform
+func IdentityAffine() Affine {
+	return Affine{{1, 0, 0}, {0, 1, 0}}
+}
+
+// Apply returns p transformed
+func (a Affine) Apply(p Point) Point {
+	return Pt(
+		a[0][0]*p.X+a[0][1]*p.Y+a[0][2],
+		a[1][0]*p.X+a[1][1]*p.Y+a[1][2],
+	)
+}
+
+// Then returns transform applying a then o
+func (a Affine) Then(o Affine) Affine {
+	var ret Affine
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 3; j++ {
+			ret[i][j] = o[i][0]*a[0][j] + o[i][1]*a[1][j]
+		}
+		ret[i][2] += o[i][2]
+	}
+	return ret
+}
+
+// Det returns determinant of the linear part, negative if the transform mirrors
+func (a Affine) Det() float64 {
+	return a[0][0]*a[1][1] - a[0][1]*a[1][0]
+}
+
+// Inverse returns inverse transform, false if a is singular
+func (a Affine) Inverse() (Affine, bool) {
+	det := a.Det()
+	if math.Abs(det) <= 1e-15 {
+		return Affine{}, false
+	}
+	ret := Affine{
+		{a[1][1] / det, -a[0][1] / det, 0},
+		{-a[1][0] / det, a[0][0] / det, 0},
+	}
+	ret[0][2] = -(ret[0][0]*a[0][2] + ret[0][1]*a[1][2])
+	ret[1][2] = -(ret[1][0]*a[0][2] + ret[1][1]*a[1][2])
+	return ret, true
+}
+
+// transformKind represents kind of a transform step
+type transformKind int
+
+const (
+	transformCrop transformKind = iota
+	transformRotate
+	transformFlipH
+	transformFlipV
+	transformResize
+)
+
+// transformStep represents a recorded transform step
+type transformStep struct {
+	kind  transformKind
+	rect  image.Rectangle
+	angle float64
+	size  image.Point
+}
+
+// Transform records crop, rotation, flip and resize steps applied to images in order.
+// The zero value is the identity transform
+type Transform struct {
+	steps []transformStep
+}
+
+// with returns a copy of t with step appended
+func (t Transform) with(step transformStep) Transform {
+	steps := make([]transformStep, len(t.steps), len(t.steps)+1)
+	copy(steps, t.steps)
+	return Transform{steps: append(steps, step)}
+}
+
+// Crop returns t followed by crop to rect, rect is in pixels relative to the top left of the image at this step
+func (t Transform) Crop(rect image.Rectangle) Transform {
+	return t.with(transformStep{kind: transformCrop, rect: rect.Canon()})
+}
+
+// Rotate returns t followed by counter-clockwise rotation by angle in degrees, the image grows to fit the rotated one
+func (t Transform) Rotate(angle float64) Transform {
+	return t.with(transformStep{kind: transformRotate, angle: angle - math.Floor(angle/360)*360})
+}
+
+// FlipH returns t followed by horizontal flip, mirrors left and right
+func (t Transform) FlipH() Transform {
+	return t.with(transformStep{kind: transformFlipH})
+}
+
+// FlipV returns t followed by vertical flip
+func (t Transform) FlipV() Transform {
+	return t.with(transformStep{kind: transformFlipV})
+}
+
+// Resize returns t followed by resize to (w, h), aspect ratio is preserved if w or h is 0
+func (t Transform) Resize(w int, h int) Transform {
+	return t.with(transformStep{kind: transformResize, size: image.Pt(w, h)})
+}
+
+// IsIdentity returns t has no step
+func (t Transform) IsIdentity() bool {
+	return len(t.steps) == 0
+}
+
+// Mirrors returns t mirrors the image, left and right body parts swap sides
+func (t Transform) Mirrors() bool {
+	var flips int
+	for _, step := range t.steps {
+		if step.kind == transformFlipH || step.kind == transformFlipV {
+			flips++
+		}
+	}
+	return flips%2 == 1
+}
+
+// Matrix returns affine transform from source to output pixel coordinates of a source image of size, and the output size.
+// Pixel coordinates are relative to the top left of the image, pixel (0, 0) covers [0, 1) x [0, 1)
+func (t Transform) Matrix(size image.Point) (Affine, image.Point) {
+	ret := IdentityAffine()
+	for _, step := range t.steps {
+		var m Affine
+		m, size = step.matrix(size)
+		ret = ret.Then(m)
+	}
+	return ret, size
+}
+
+// matrix returns affine transform of step and output size of source image size
+func (s transformStep) matrix(size image.Point) (Affine, image.Point) {
+	w, h := float64(size.X), float64(size.Y)
+	switch s.kind {
+	case transformCrop:
+		r := s.rect.Intersect(image.Rect(0, 0, size.X, size.Y))
+		return Affine{{1, 0, -float64(r.Min.X)}, {0, 1, -float64(r.Min.Y)}}, r.Size()
+	case transformRotate:
+		out := rotatedSize(size, s.angle)
+		// rotation about the image centers, counter-clockwise on screen with y pointing down
+		sin, cos := math.Sincos(s.angle * math.Pi / 180)
+		m := Affine{{cos, sin, 0}, {-sin, cos, 0}}
+		m[0][2] = float64(out.X)/2 - (cos*w/2 + sin*h/2)
+		m[1][2] = float64(out.Y)/2 - (-sin*w/2 + cos*h/2)
+		return m, out
+	case transformFlipH:
+		return Affine{{-1, 0, w}, {0, 1, 0}}, size
+	case transformFlipV:
+		return Affine{{1, 0, 0}, {0, -1, h}}, size
+	case transformResize:
+		out := resizedSize(size, s.size)
+		if size.X <= 0 || size.Y <= 0 {
+			return IdentityAffine(), out
+		}
+		return Affine{{float64(out.X) / w, 0, 0}, {0, float64(out.Y) / h, 0}}, out
+	}
+	return IdentityAffine(), size
+}
+
+// rotatedSize returns image size rotated by angle in degrees, as imaging.Rotate
+func rotatedSize(size image.Point, angle float64) image.Point {
+	switch angle {
+	case 0, 180:
+		return size
+	case 90, 270:
+		return image.Pt(size.Y, size.X)
+	}
+	if size.X <= 0 || size.Y <= 0 {
+		return image.Point{}
+	}
+	sin, cos := math.Sincos(math.Pi * angle / 180)
+	w, h := float64(size.X-1), float64(size.Y-1)
+	xs := []float64{0, w * cos, w*cos - h*sin, -h * sin}
+	ys := []float64{0, w * sin, w*sin + h*cos, h * cos}
+	extent := func(values []float64) int {
+		min, max := values[0], values[0]
+		for _, v := range values[1:] {
+			min, max = math.Min(min, v), math.Max(max, v)
+		}
+		ret := max - min + 1
+		if ret-math.Floor(ret) > 0.1 {
+			ret++
+		}
+		return int(ret)
+	}
+	return image.Pt(extent(xs), extent(ys))
+}
+
+// resizedSize returns image size resized to target, as imaging.Resize
+func resizedSize(size image.Point, target image.Point) image.Point {
+	if size.X <= 0 || size.Y <= 0 || target.X < 0 || target.Y < 0 || (target.X == 0 && target.Y == 0) {
+		return image.Point{}
+	}
+	if target.X == 0 {
+		target.X = int(math.Max(1, math.Floor(float64(target.Y)*float64(size.X)/float64(size.Y)+0.5)))
+	}
+	if target.Y == 0 {
+		target.Y = int(math.Max(1, math.Floor(float64(target.X)*float64(size.Y)/float64(size.X)+0.5)))
+	}
+	return target
+}
+
+// Apply returns img transformed, the output origin is (0, 0). Sub-images with non-zero bounds min are supported
+func (t Transform) Apply(img image.Image) image.Image {
+	if t.IsIdentity() {
+		return img
+	}
+	out := img
+	for _, step := range t.steps {
+		bounds := out.Bounds()
+		switch step.kind {
+		case transformCrop:
+			out = imaging.Crop(out, step.rect.Add(bounds.Min))
+		case transformRotate:
+			out = imaging.Rotate(out, step.angle, color.Transparent)
+		case transformFlipH:
+			out = imaging.FlipH(out)
+		case transformFlipV:
+			out = imaging.FlipV(out)
+		case transformResize:
+			out = imaging.Resize(out, step.size.X, step.size.Y, imaging.Linear)
+		}
+	}
+	return out
+}
+
+// Human returns human estimated in the image t outputs of a source image of bounds with points mapped back to
+// the source image, points are normalized to the source size. Left and right parts are swapped back if t mirrors
+func (t Transform) Human(h Human, bounds image.Rectangle) Human {
+	if t.IsIdentity() {
+		return h
+	}
+	size := bounds.Size()
+	m, out := t.Matrix(size)
+	inv, ok := m.Inverse()
+	if !ok || size.X <= 0 || size.Y <= 0 {
+		return h
+	}
+	mirrors := t.Mirrors()
+	ret := Human{
+		Parts: make(map[CocoPart]BodyPart, len(h.Parts)),
+		Score: h.Score,
+	}
+	for part, bodyPart := range h.Parts {
+		p := inv.Apply(bodyPart.Point.Scale(float64(out.X), float64(out.Y)))
+		bodyPart.Point = Pt(p.X/float64(size.X), p.Y/float64(size.Y))
+		if mirrors {
+			part = part.Mirror()
+			bodyPart.Part = part
+		}
+		ret.Parts[part] = bodyPart
+	}
+	return ret
+}
+
+// Humans returns humans mapped back to the source image of bounds, see Human
+func (t Transform) Humans(humans []Human, bounds image.Rectangle) []Human {
+	if t.IsIdentity() {
+		return humans
+	}
+	ret := make([]Human, 0, len(humans))
+	for _, h := range humans {
+		ret = append(ret, t.Human(h, bounds))
+	}
+	return ret
+}
@@ -0,0 +1,120 @@
+package openpose
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// markedImage returns a sub-image of bounds with non-zero min, a 3x3 red mark is centered on pixel (x, y) relative to its top left
+func markedImage(bounds image.Rectangle, x int, y int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, bounds.Max.X+20, bounds.Max.Y+20))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	mark := image.Rect(x-1, y-1, x+2, y+2).Add(bounds.Min)
+	draw.Draw(img, mark, image.NewUniform(color.RGBA{255, 0, 0, 255}), image.Point{}, draw.Src)
+	return img.SubImage(bounds)
+}
+
+// markCenter returns red weighted center of img relative to its top left, pixel (0, 0) covers [0, 1) x [0, 1)
+func markCenter(img image.Image) (Point, bool) {
+	bounds := img.Bounds()
+	var sx, sy, sum float64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, _, _ := img.At(x, y).RGBA()
+			if r <= g {
+				continue
+			}
+			v := float64(r - g)
+			sx += v * (float64(x-bounds.Min.X) + 0.5)
+			sy += v * (float64(y-bounds.Min.Y) + 0.5)
+			sum += v
+		}
+	}
+	if sum == 0 {
+		return ZP, false
+	}
+	return Pt(sx/sum, sy/sum), true
+}
+
+func TestTransform_Matrix(t *testing.T) {
+	bounds := image.Rect(30, 20, 230, 140)
+	src := markedImage(bounds, 90, 40)
+	center := Pt(90.5, 40.5)
+	tests := []struct {
+		name      string
+		transform Transform
+	}{
+		{"rotate 90", Transform{}.Rotate(90)},
+		{"rotate 30", Transform{}.Rotate(30)},
+		{"rotate -90", Transform{}.Rotate(-90)},
+		{"flip h", Transform{}.FlipH()},
+		{"flip v", Transform{}.FlipV()},
+		{"resize width", Transform{}.Resize(100, 0)},
+		{"resize height", Transform{}.Resize(0, 77)},
+		{"crop", Transform{}.Crop(image.Rect(50, 10, 150, 100))},
+		{"chain", Transform{}.Crop(image.Rect(20, 5, 180, 110)).Rotate(30).FlipH().Resize(0, 90)},
+	}
+	for _, tt := range tests {
+		out := tt.transform.Apply(src)
+		m, size := tt.transform.Matrix(bounds.Size())
+		assert.Equal(t, image.Point{}, out.Bounds().Min, tt.name)
+		assert.Equal(t, size, out.Bounds().Size(), tt.name)
+		got, found := markCenter(out)
+		if !assert.True(t, found, tt.name) {
+			continue
+		}
+		want := m.Apply(center)
+		assert.InDelta(t, want.X, got.X, 0.75, tt.name)
+		assert.InDelta(t, want.Y, got.Y, 0.75, tt.name)
+	}
+}
+
+func TestTransform_Human(t *testing.T) {
+	bounds := image.Rect(30, 20, 230, 140)
+	size := bounds.Size()
+	points := map[CocoPart]Point{
+		CocoPartNose:      Pt(100, 30),
+		CocoPartRShoulder: Pt(80, 50),
+		CocoPartLShoulder: Pt(120, 52),
+		CocoPartRWrist:    Pt(60, 90),
+	}
+	source := NewHuman()
+	for part, point := range points {
+		source.Parts[part] = NewBodyPart(part, Pt(point.X/float64(size.X), point.Y/float64(size.Y)), 0.8)
+	}
+	for _, transform := range []Transform{
+		Transform{}.Rotate(30).Resize(0, 90),
+		Transform{}.Crop(image.Rect(20, 5, 180, 110)).FlipH(),
+		Transform{}.Rotate(90).FlipV().FlipH(),
+	} {
+		// human as estimated in the transformed image
+		m, out := transform.Matrix(size)
+		estimated := NewHuman()
+		for part, point := range points {
+			p := m.Apply(point)
+			if transform.Mirrors() {
+				part = part.Mirror()
+			}
+			estimated.Parts[part] = NewBodyPart(part, Pt(p.X/float64(out.X), p.Y/float64(out.Y)), 0.8)
+		}
+		got := transform.Human(*estimated, bounds)
+		if !assert.Len(t, got.Parts, len(source.Parts)) {
+			continue
+		}
+		for part, want := range source.Parts {
+			bodyPart, found := got.Parts[part]
+			if !assert.True(t, found, "%s", part) {
+				continue
+			}
+			assert.Equal(t, part, bodyPart.Part)
+			assert.InDelta(t, want.Point.X, bodyPart.Point.X, 1e-9)
+			assert.InDelta(t, want.Point.Y, bodyPart.Point.Y, 1e-9)
+		}
+	}
+	assert.True(t, Transform{}.FlipH().Mirrors())
+	assert.False(t, Transform{}.FlipH().FlipV().Mirrors())
+}